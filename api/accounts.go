@@ -0,0 +1,153 @@
+package api
+
+import (
+	"errors"
+	"mononoke-go/entities"
+	"mononoke-go/mail"
+	"mononoke-go/model"
+	"net/http"
+)
+
+type account struct {
+	AccountID          uint32 `json:"accountId"`
+	AccountName        string `json:"accountName"`
+	Email              string `json:"email"`
+	EmailVerified      bool   `json:"emailVerified"`
+	Blocked            bool   `json:"blocked"`
+	Age                uint8  `json:"age"`
+	Permission         uint32 `json:"permission"`
+	LastLoginServerIdx uint32 `json:"lastLoginServerIdx"`
+	TOTPEnabled        bool   `json:"totpEnabled"`
+}
+
+func newAccount(a *model.Accounts) account {
+	return account{
+		AccountID:          a.AccountID,
+		AccountName:        a.AccountName,
+		Email:              a.Email,
+		EmailVerified:      !a.EmailVerificationPending,
+		Blocked:            a.Blocked,
+		Age:                a.Age,
+		Permission:         a.Permission,
+		LastLoginServerIdx: a.LastLoginServerIdx,
+		TOTPEnabled:        a.TOTPEnabled,
+	}
+}
+
+func (s *Server) createAccount(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		AccountName string `json:"accountName"`
+		Password    string `json:"password"`
+		Email       string `json:"email"`
+		Permission  uint32 `json:"permission"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	created, err := s.Accounts.Create(request.AccountName, request.Password, request.Email, request.Permission)
+	if err != nil {
+		s.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newAccount(created))
+}
+
+func (s *Server) getAccount(w http.ResponseWriter, r *http.Request) {
+	found, err := s.Accounts.Get(r.PathValue("name"))
+	if err != nil {
+		s.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newAccount(found))
+}
+
+func (s *Server) deleteAccount(w http.ResponseWriter, r *http.Request) {
+	s.writeResult(w, s.Accounts.Delete(r.PathValue("name")))
+}
+
+func (s *Server) changePassword(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Password string `json:"password"`
+	}
+	if readJSON(w, r, &request) {
+		s.writeResult(w, s.Accounts.ChangePassword(r.PathValue("name"), request.Password))
+	}
+}
+
+func (s *Server) changeEmail(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Email string `json:"email"`
+	}
+	if readJSON(w, r, &request) {
+		s.writeResult(w, s.Accounts.ChangeEmail(r.PathValue("name"), request.Email))
+	}
+}
+
+func (s *Server) setBlocked(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Blocked bool `json:"blocked"`
+	}
+	if readJSON(w, r, &request) {
+		s.writeResult(w, s.Accounts.SetBlocked(r.PathValue("name"), request.Blocked))
+	}
+}
+
+func (s *Server) setFatigueLimit(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Limited *bool `json:"limited"`
+	}
+	if readJSON(w, r, &request) {
+		s.writeResult(w, s.Accounts.SetFatigueLimit(r.PathValue("name"), request.Limited))
+	}
+}
+
+func (s *Server) setPermission(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Permission uint32 `json:"permission"`
+	}
+	if readJSON(w, r, &request) {
+		s.writeResult(w, s.Accounts.SetPermission(r.PathValue("name"), request.Permission))
+	}
+}
+
+func (s *Server) setSecurityCode(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		SecurityCode string `json:"securityCode"`
+	}
+	if readJSON(w, r, &request) {
+		s.writeResult(w, s.Accounts.SetSecurityCode(r.PathValue("name"), request.SecurityCode))
+	}
+}
+
+func (s *Server) writeResult(w http.ResponseWriter, err error) {
+	if err != nil {
+		s.writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entities.ErrAccountNotFound), errors.Is(err, entities.ErrHardwareBanNotFound),
+		errors.Is(err, entities.ErrNoPremium), errors.Is(err, entities.ErrEventCodeNotFound),
+		errors.Is(err, entities.ErrIPBanNotFound), errors.Is(err, entities.ErrNotBanned):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, entities.ErrLauncherDisabled):
+		writeError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, mail.ErrMailDisabled):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, entities.ErrAccountNameTaken), errors.Is(err, entities.ErrEmailTaken):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, entities.ErrInvalidAccountName), errors.Is(err, entities.ErrInvalidEmail),
+		errors.Is(err, entities.ErrInvalidPassword), errors.Is(err, entities.ErrInvalidToken),
+		errors.Is(err, entities.ErrEmailRequired), errors.Is(err, entities.ErrInvalidMacStamp),
+		errors.Is(err, entities.ErrInvalidEventPeriod), errors.Is(err, entities.ErrInvalidNetwork):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		s.Log.Error("Admin API request failed",
+			"function", "Server::writeServiceError",
+			"error", err.Error())
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
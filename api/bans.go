@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+func (s *Server) listAccountBans(w http.ResponseWriter, r *http.Request) {
+	bans, err := s.Bans.AccountBans(r.PathValue("name"))
+	if err != nil {
+		s.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, bans)
+}
+
+func (s *Server) banAccount(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Days   uint32 `json:"days"`
+		Reason string `json:"reason"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	ban, err := s.Bans.BanAccount(r.PathValue("name"), time.Duration(request.Days)*24*time.Hour, request.Reason, "api")
+	if err != nil {
+		s.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"endsAt": ban.EndsAt})
+}
+
+func (s *Server) liftAccountBan(w http.ResponseWriter, r *http.Request) {
+	s.writeResult(w, s.Bans.LiftAccountBan(r.PathValue("name")))
+}
+
+func (s *Server) listIPBans(w http.ResponseWriter, _ *http.Request) {
+	bans, err := s.Bans.IPBans()
+	if err != nil {
+		s.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, bans)
+}
+
+func (s *Server) banIP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Network   string     `json:"network"`
+		Reason    string     `json:"reason"`
+		ExpiresAt *time.Time `json:"expiresAt"`
+	}
+	if readJSON(w, r, &request) {
+		s.writeResult(w, s.Bans.BanIP(request.Network, request.Reason, "api", request.ExpiresAt))
+	}
+}
+
+func (s *Server) unbanIP(w http.ResponseWriter, r *http.Request) {
+	s.writeResult(w, s.Bans.UnbanIP(r.PathValue("network")))
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (s *Server) sharedMacStamps(w http.ResponseWriter, r *http.Request) {
+	minAccounts := 2
+	if value := r.URL.Query().Get("min"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid min")
+			return
+		}
+		minAccounts = parsed
+	}
+	shared, err := s.Hardware.SharedMacStamps(minAccounts)
+	if err != nil {
+		s.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, shared)
+}
+
+func (s *Server) banMacStamp(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		MacStamp  string     `json:"macStamp"`
+		Reason    string     `json:"reason"`
+		ExpiresAt *time.Time `json:"expiresAt"`
+	}
+	if readJSON(w, r, &request) {
+		s.writeResult(w, s.Hardware.Ban(request.MacStamp, request.Reason, "api", request.ExpiresAt))
+	}
+}
+
+func (s *Server) unbanMacStamp(w http.ResponseWriter, r *http.Request) {
+	s.writeResult(w, s.Hardware.Unban(r.PathValue("macStamp")))
+}
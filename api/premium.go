@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (s *Server) grantPremium(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Days   uint32 `json:"days"`
+		Reason string `json:"reason"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	entitlement, err := s.Premium.Grant(r.PathValue("name"), time.Duration(request.Days)*24*time.Hour, request.Reason)
+	if err != nil {
+		s.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"endsAt": entitlement.EndsAt})
+}
+
+func (s *Server) revokePremium(w http.ResponseWriter, r *http.Request) {
+	s.writeResult(w, s.Premium.Revoke(r.PathValue("name")))
+}
+
+func (s *Server) listEventCodes(w http.ResponseWriter, _ *http.Request) {
+	eventCodes, err := s.Premium.EventCodes()
+	if err != nil {
+		s.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, eventCodes)
+}
+
+func (s *Server) addEventCode(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		AccountName string     `json:"accountName"`
+		Code        uint32     `json:"code"`
+		Campaign    string     `json:"campaign"`
+		StartsAt    *time.Time `json:"startsAt"`
+		EndsAt      *time.Time `json:"endsAt"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	startsAt := time.Now()
+	if request.StartsAt != nil {
+		startsAt = *request.StartsAt
+	}
+	eventCode, err := s.Premium.AddEventCode(request.AccountName, request.Code, request.Campaign, startsAt,
+		request.EndsAt)
+	if err != nil {
+		s.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, eventCode)
+}
+
+func (s *Server) removeEventCode(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+	s.writeResult(w, s.Premium.RemoveEventCode(uint32(id)))
+}
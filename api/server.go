@@ -0,0 +1,118 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"mononoke-go/entities"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrMissingToken = errors.New("admin API needs a token")
+
+const readHeaderTimeout = 10 * time.Second
+
+// Server is the HTTP admin API, every request has to send the configured token as bearer token.
+// Launcher tokens are issued with the separate LauncherToken, which grants nothing else.
+type Server struct {
+	Accounts      *entities.AccountService
+	Hardware      *entities.HardwareService
+	Premium       *entities.PremiumService
+	Bans          *entities.BanService
+	Token         string
+	LauncherToken string
+	Log           *slog.Logger
+}
+
+// ListenAndServe serves the admin API on the address until an error occurs.
+func (s *Server) ListenAndServe(addr string) error {
+	if s.Token == "" {
+		return ErrMissingToken
+	}
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	s.Log.Info("Admin API listening",
+		"function", "Server::ListenAndServe",
+		"address", addr)
+	return server.ListenAndServe()
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("POST /accounts/{name}/launchertoken",
+		s.authorize(s.LauncherToken, http.HandlerFunc(s.issueLauncherToken)))
+	mux.Handle("/", s.authorize(s.Token, s.adminHandler()))
+	return mux
+}
+
+func (s *Server) adminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /accounts", s.createAccount)
+	mux.HandleFunc("GET /accounts/{name}", s.getAccount)
+	mux.HandleFunc("DELETE /accounts/{name}", s.deleteAccount)
+	mux.HandleFunc("PUT /accounts/{name}/password", s.changePassword)
+	mux.HandleFunc("PUT /accounts/{name}/email", s.changeEmail)
+	mux.HandleFunc("PUT /accounts/{name}/blocked", s.setBlocked)
+	mux.HandleFunc("PUT /accounts/{name}/permission", s.setPermission)
+	mux.HandleFunc("PUT /accounts/{name}/securitycode", s.setSecurityCode)
+	mux.HandleFunc("PUT /accounts/{name}/fatigue", s.setFatigueLimit)
+	mux.HandleFunc("POST /accounts/{name}/verification", s.sendVerification)
+	mux.HandleFunc("POST /accounts/{name}/passwordreset", s.requestPasswordReset)
+	mux.HandleFunc("POST /verification", s.verifyEmail)
+	mux.HandleFunc("POST /passwordreset", s.resetPassword)
+	mux.HandleFunc("POST /accounts/{name}/premium", s.grantPremium)
+	mux.HandleFunc("DELETE /accounts/{name}/premium", s.revokePremium)
+	mux.HandleFunc("GET /eventcodes", s.listEventCodes)
+	mux.HandleFunc("POST /eventcodes", s.addEventCode)
+	mux.HandleFunc("DELETE /eventcodes/{id}", s.removeEventCode)
+	mux.HandleFunc("GET /hardware/shared", s.sharedMacStamps)
+	mux.HandleFunc("POST /hardware/bans", s.banMacStamp)
+	mux.HandleFunc("DELETE /hardware/bans/{macStamp}", s.unbanMacStamp)
+	mux.HandleFunc("GET /accounts/{name}/bans", s.listAccountBans)
+	mux.HandleFunc("POST /accounts/{name}/bans", s.banAccount)
+	mux.HandleFunc("DELETE /accounts/{name}/bans", s.liftAccountBan)
+	mux.HandleFunc("GET /bans/ips", s.listIPBans)
+	mux.HandleFunc("POST /bans/ips", s.banIP)
+	mux.HandleFunc("DELETE /bans/ips/{network...}", s.unbanIP)
+	return mux
+}
+
+// authorize only passes requests with the expected bearer token, an empty token rejects every request.
+func (s *Server) authorize(expected string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || expected == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+			s.Log.Warn("Unauthorized admin API request",
+				"function", "Server::authorize",
+				"remoteAddr", r.RemoteAddr,
+				"path", r.URL.Path)
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, value interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(value); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
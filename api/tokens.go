@@ -0,0 +1,39 @@
+package api
+
+import "net/http"
+
+func (s *Server) issueLauncherToken(w http.ResponseWriter, r *http.Request) {
+	token, expiresAt, err := s.Accounts.IssueLauncherToken(r.PathValue("name"))
+	if err != nil {
+		s.writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"token": token, "expiresAt": expiresAt})
+}
+
+func (s *Server) sendVerification(w http.ResponseWriter, r *http.Request) {
+	s.writeResult(w, s.Accounts.SendVerification(r.PathValue("name")))
+}
+
+func (s *Server) requestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	s.writeResult(w, s.Accounts.RequestPasswordReset(r.PathValue("name")))
+}
+
+func (s *Server) verifyEmail(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Token string `json:"token"`
+	}
+	if readJSON(w, r, &request) {
+		s.writeResult(w, s.Accounts.VerifyEmail(request.Token))
+	}
+}
+
+func (s *Server) resetPassword(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if readJSON(w, r, &request) {
+		s.writeResult(w, s.Accounts.ResetPassword(request.Token, request.Password))
+	}
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+func (c *CLI) accountCreate(args []string) error {
+	email := ""
+	if len(args) > 2 {
+		email = args[2]
+	}
+	permission := uint64(0)
+	if len(args) > 3 {
+		var err error
+		if permission, err = strconv.ParseUint(args[3], 10, 32); err != nil {
+			return fmt.Errorf("invalid permission %s: %w", args[3], err)
+		}
+	}
+
+	account, err := c.Accounts.Create(args[0], args[1], email, uint32(permission))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Account %s created with ID %d\n", account.AccountName, account.AccountID)
+	return nil
+}
+
+func (c *CLI) accountPassword(args []string) error {
+	if err := c.Accounts.ChangePassword(args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Password of %s changed\n", args[0])
+	return nil
+}
+
+func (c *CLI) accountEmail(args []string) error {
+	if err := c.Accounts.ChangeEmail(args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Email of %s changed to %s\n", args[0], args[1])
+	return nil
+}
+
+func (c *CLI) accountBlock(args []string) error {
+	if err := c.Accounts.SetBlocked(args[0], true); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Account %s blocked\n", args[0])
+	return nil
+}
+
+func (c *CLI) accountUnblock(args []string) error {
+	if err := c.Accounts.SetBlocked(args[0], false); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Account %s unblocked\n", args[0])
+	return nil
+}
+
+func (c *CLI) accountPermission(args []string) error {
+	permission, err := strconv.ParseUint(args[1], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid permission %s: %w", args[1], err)
+	}
+	if err = c.Accounts.SetPermission(args[0], uint32(permission)); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Permission of %s set to %d\n", args[0], permission)
+	return nil
+}
+
+func (c *CLI) accountSecurityCode(args []string) error {
+	if err := c.Accounts.SetSecurityCode(args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Security code of %s changed\n", args[0])
+	return nil
+}
+
+func (c *CLI) accountDelete(args []string) error {
+	if err := c.Accounts.Delete(args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Account %s deleted\n", args[0])
+	return nil
+}
+
+func (c *CLI) accountFatigue(args []string) error {
+	var limit *bool
+	switch args[1] {
+	case "on", "off":
+		limited := args[1] == "on"
+		limit = &limited
+	case "default":
+	default:
+		return fmt.Errorf("%w: %s, use on, off or default", ErrInvalidFatigueSetting, args[1])
+	}
+	if err := c.Accounts.SetFatigueLimit(args[0], limit); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Fatigue limit of %s set to %s\n", args[0], args[1])
+	return nil
+}
+
+func (c *CLI) accountBirthdate(args []string) error {
+	account, err := c.account(args[0])
+	if err != nil {
+		return err
+	}
+
+	birthdate, err := time.Parse(time.DateOnly, args[1])
+	if err != nil {
+		return fmt.Errorf("invalid birthdate %s: %w", args[1], err)
+	}
+	if err = c.DB.SetBirthdate(account.AccountID, birthdate); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Birthdate of %s set to %s\n", account.AccountName, birthdate.Format(time.DateOnly))
+	return nil
+}
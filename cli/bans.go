@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (c *CLI) accountBan(args []string) error {
+	duration, err := parseDays(args[1])
+	if err != nil {
+		return err
+	}
+	ban, err := c.Bans.BanAccount(args[0], duration, strings.Join(args[2:], " "), "cli")
+	if err != nil {
+		return err
+	}
+	if ban.EndsAt == nil {
+		fmt.Fprintf(c.Out, "%s banned permanently\n", args[0])
+		return nil
+	}
+	fmt.Fprintf(c.Out, "%s banned until %s\n", args[0], ban.EndsAt.Format(time.DateTime))
+	return nil
+}
+
+func (c *CLI) accountUnban(args []string) error {
+	if err := c.Bans.LiftAccountBan(args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Ban of %s lifted\n", args[0])
+	return nil
+}
+
+func (c *CLI) accountBanHistory(args []string) error {
+	bans, err := c.Bans.AccountBans(args[0])
+	if err != nil {
+		return err
+	}
+	if len(bans) == 0 {
+		fmt.Fprintf(c.Out, "%s was never banned\n", args[0])
+		return nil
+	}
+	for _, ban := range bans {
+		ends := "never"
+		if ban.EndsAt != nil {
+			ends = ban.EndsAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(c.Out, "  %s until %-19s by %-10s %s\n", ban.StartsAt.Format(time.DateTime), ends, ban.Issuer, ban.Reason)
+	}
+	return nil
+}
+
+func (c *CLI) ipBan(args []string) error {
+	if err := c.Bans.BanIP(args[0], strings.Join(args[1:], " "), "cli", nil); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "%s banned, running servers apply it with the next ban list reload\n", args[0])
+	return nil
+}
+
+func (c *CLI) ipUnban(args []string) error {
+	if err := c.Bans.UnbanIP(args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "%s unbanned, running servers apply it with the next ban list reload\n", args[0])
+	return nil
+}
+
+func (c *CLI) ipBanList(_ []string) error {
+	bans, err := c.Bans.IPBans()
+	if err != nil {
+		return err
+	}
+	if len(bans) == 0 {
+		fmt.Fprintln(c.Out, "No IP bans in the database")
+		return nil
+	}
+	for _, ban := range bans {
+		expires := "never"
+		if ban.ExpiresAt != nil {
+			expires = ban.ExpiresAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(c.Out, "  %-20s expires %-19s by %-10s %s\n", ban.Network, expires, ban.CreatedBy, ban.Reason)
+	}
+	return nil
+}
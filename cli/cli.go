@@ -0,0 +1,310 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mononoke-go/config"
+	"mononoke-go/database"
+	"mononoke-go/entities"
+	"mononoke-go/model"
+	"sort"
+)
+
+var (
+	ErrUnknownCommand        = errors.New("unknown command")
+	ErrMissingArguments      = errors.New("missing arguments")
+	ErrAccountNotFound       = errors.New("account not found")
+	ErrInvalidFatigueSetting = errors.New("invalid fatigue setting")
+)
+
+type command struct {
+	Usage       string
+	Description string
+	MinArgs     int
+	Run         func(args []string) error
+}
+
+// CLI runs administrative commands directly against the database.
+type CLI struct {
+	DB       *database.GormDatabase
+	Config   *config.Configuration
+	Accounts *entities.AccountService
+	Hardware *entities.HardwareService
+	Premium  *entities.PremiumService
+	Bans     *entities.BanService
+	Out      io.Writer
+}
+
+func (c *CLI) commands() map[string]command {
+	return map[string]command{
+		"account-create": {
+			Usage:       "account-create <account> <password> [email] [permission]",
+			Description: "creates a new account",
+			MinArgs:     2,
+			Run:         c.accountCreate,
+		},
+		"account-password": {
+			Usage:       "account-password <account> <password>",
+			Description: "changes the password of an account",
+			MinArgs:     2,
+			Run:         c.accountPassword,
+		},
+		"account-email": {
+			Usage:       "account-email <account> <email>",
+			Description: "changes the email address of an account",
+			MinArgs:     2,
+			Run:         c.accountEmail,
+		},
+		"account-block": {
+			Usage:       "account-block <account>",
+			Description: "blocks an account",
+			MinArgs:     1,
+			Run:         c.accountBlock,
+		},
+		"account-unblock": {
+			Usage:       "account-unblock <account>",
+			Description: "unblocks an account",
+			MinArgs:     1,
+			Run:         c.accountUnblock,
+		},
+		"account-permission": {
+			Usage:       "account-permission <account> <permission>",
+			Description: "sets the permission of an account",
+			MinArgs:     2,
+			Run:         c.accountPermission,
+		},
+		"account-securitycode": {
+			Usage:       "account-securitycode <account> <code>",
+			Description: "sets the secondary password checked by game servers",
+			MinArgs:     2,
+			Run:         c.accountSecurityCode,
+		},
+		"account-delete": {
+			Usage:       "account-delete <account>",
+			Description: "deletes an account with all its data except the audit trail",
+			MinArgs:     1,
+			Run:         c.accountDelete,
+		},
+		"verification-send": {
+			Usage:       "verification-send <account>",
+			Description: "sends a new email verification token",
+			MinArgs:     1,
+			Run:         c.verificationSend,
+		},
+		"verification-use": {
+			Usage:       "verification-use <token>",
+			Description: "verifies an email address with a token",
+			MinArgs:     1,
+			Run:         c.verificationUse,
+		},
+		"password-reset-request": {
+			Usage:       "password-reset-request <account>",
+			Description: "sends a password reset token",
+			MinArgs:     1,
+			Run:         c.passwordResetRequest,
+		},
+		"password-reset": {
+			Usage:       "password-reset <token> <password>",
+			Description: "sets a new password with a password reset token",
+			MinArgs:     2,
+			Run:         c.passwordReset,
+		},
+		"account-ban": {
+			Usage:       "account-ban <account> <days> [reason]",
+			Description: "bans an account for the number of days, 0 bans it permanently",
+			MinArgs:     2,
+			Run:         c.accountBan,
+		},
+		"account-unban": {
+			Usage:       "account-unban <account>",
+			Description: "lifts the active bans of an account",
+			MinArgs:     1,
+			Run:         c.accountUnban,
+		},
+		"account-ban-history": {
+			Usage:       "account-ban-history <account>",
+			Description: "lists all bans of an account",
+			MinArgs:     1,
+			Run:         c.accountBanHistory,
+		},
+		"ip-ban": {
+			Usage:       "ip-ban <ip|cidr> [reason]",
+			Description: "rejects connections from the IP or CIDR range",
+			MinArgs:     1,
+			Run:         c.ipBan,
+		},
+		"ip-unban": {
+			Usage:       "ip-unban <ip|cidr>",
+			Description: "removes the ban of an IP or CIDR range",
+			MinArgs:     1,
+			Run:         c.ipUnban,
+		},
+		"ip-ban-list": {
+			Usage:       "ip-ban-list",
+			Description: "lists the active IP bans of the database",
+			MinArgs:     0,
+			Run:         c.ipBanList,
+		},
+		"hardware-ban": {
+			Usage:       "hardware-ban <macstamp> [reason]",
+			Description: "rejects logins of all accounts from the machine",
+			MinArgs:     1,
+			Run:         c.hardwareBan,
+		},
+		"hardware-unban": {
+			Usage:       "hardware-unban <macstamp>",
+			Description: "removes the ban of a machine",
+			MinArgs:     1,
+			Run:         c.hardwareUnban,
+		},
+		"hardware-report": {
+			Usage:       "hardware-report [minaccounts]",
+			Description: "lists machines used by at least 2 or the given number of accounts",
+			MinArgs:     0,
+			Run:         c.hardwareReport,
+		},
+		"premium-grant": {
+			Usage:       "premium-grant <account> <days> [reason]",
+			Description: "grants premium for the number of days, 0 grants it permanently",
+			MinArgs:     2,
+			Run:         c.premiumGrant,
+		},
+		"premium-revoke": {
+			Usage:       "premium-revoke <account>",
+			Description: "ends the premium of an account",
+			MinArgs:     1,
+			Run:         c.premiumRevoke,
+		},
+		"event-code-add": {
+			Usage:       "event-code-add <account|all> <code> [days] [campaign]",
+			Description: "assigns an event code to an account or to all accounts",
+			MinArgs:     2,
+			Run:         c.eventCodeAdd,
+		},
+		"event-code-remove": {
+			Usage:       "event-code-remove <id>",
+			Description: "removes an event code assignment",
+			MinArgs:     1,
+			Run:         c.eventCodeRemove,
+		},
+		"event-code-list": {
+			Usage:       "event-code-list",
+			Description: "lists the active event code assignments",
+			MinArgs:     0,
+			Run:         c.eventCodeList,
+		},
+		"server-set": {
+			Usage:       "server-set <serverIdx> <key=value>...",
+			Description: "overrides name, ip, port, screenshot, adult, order, hidden or staffonly of a server",
+			MinArgs:     2,
+			Run:         c.serverSet,
+		},
+		"server-remove": {
+			Usage:       "server-remove <serverIdx>",
+			Description: "removes the database entry of a server",
+			MinArgs:     1,
+			Run:         c.serverRemove,
+		},
+		"server-list": {
+			Usage:       "server-list",
+			Description: "lists the server entries of the database",
+			MinArgs:     0,
+			Run:         c.serverList,
+		},
+		"account-fatigue": {
+			Usage:       "account-fatigue <account> <on|off|default>",
+			Description: "always or never limits the play time of an account, default applies the configuration",
+			MinArgs:     2,
+			Run:         c.accountFatigue,
+		},
+		"account-birthdate": {
+			Usage:       "account-birthdate <account> <YYYY-MM-DD>",
+			Description: "sets the birthdate used to calculate the age of an account",
+			MinArgs:     2,
+			Run:         c.accountBirthdate,
+		},
+		"eula-publish": {
+			Usage:       "eula-publish <version> [description]",
+			Description: "publishes a new EULA version which has to be accepted",
+			MinArgs:     1,
+			Run:         c.eulaPublish,
+		},
+		"eula-accept": {
+			Usage:       "eula-accept <account> [version]",
+			Description: "records the acceptance of the current or given EULA version",
+			MinArgs:     1,
+			Run:         c.eulaAccept,
+		},
+		"maintenance-on": {
+			Usage:       "maintenance-on <all|serverIdx,...> [message]",
+			Description: "starts a maintenance for all or the given servers",
+			MinArgs:     1,
+			Run:         c.maintenanceOn,
+		},
+		"maintenance-off": {
+			Usage:       "maintenance-off <all|serverIdx,...>",
+			Description: "stops the maintenance of all or the given servers",
+			MinArgs:     1,
+			Run:         c.maintenanceOff,
+		},
+		"maintenance-status": {
+			Usage:       "maintenance-status",
+			Description: "lists the active maintenances",
+			MinArgs:     0,
+			Run:         c.maintenanceStatus,
+		},
+		"totp-enroll": {
+			Usage:       "totp-enroll <account>",
+			Description: "enables TOTP for an account and prints the secret and recovery codes",
+			MinArgs:     1,
+			Run:         c.totpEnroll,
+		},
+		"totp-reset": {
+			Usage:       "totp-reset <account>",
+			Description: "removes the TOTP enrollment and recovery codes of an account",
+			MinArgs:     1,
+			Run:         c.totpReset,
+		},
+	}
+}
+
+// Run executes the command named by the first argument.
+func (c *CLI) Run(args []string) error {
+	commands := c.commands()
+	if len(args) == 0 || args[0] == "help" {
+		c.printUsage(commands)
+		return nil
+	}
+
+	cmd, exists := commands[args[0]]
+	if !exists {
+		c.printUsage(commands)
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, args[0])
+	}
+	if len(args)-1 < cmd.MinArgs {
+		return fmt.Errorf("%w, usage: %s", ErrMissingArguments, cmd.Usage)
+	}
+	return cmd.Run(args[1:])
+}
+
+func (c *CLI) printUsage(commands map[string]command) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(c.Out, "Available commands:")
+	for _, name := range names {
+		fmt.Fprintf(c.Out, "  %-40s %s\n", commands[name].Usage, commands[name].Description)
+	}
+}
+
+func (c *CLI) account(name string) (*model.Accounts, error) {
+	account, found := c.DB.GetUserByName(name)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, name)
+	}
+	return account, nil
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNoEulaPublished = errors.New("no EULA version published")
+
+func (c *CLI) eulaPublish(args []string) error {
+	version, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid version %s: %w", args[0], err)
+	}
+	description := strings.Join(args[1:], " ")
+	if err = c.DB.PublishEulaVersion(uint32(version), description, time.Now()); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "EULA version %d published\n", version)
+	return nil
+}
+
+func (c *CLI) eulaAccept(args []string) error {
+	account, err := c.account(args[0])
+	if err != nil {
+		return err
+	}
+
+	version, exists := c.DB.GetCurrentEulaVersion()
+	if len(args) > 1 {
+		parsed, parseErr := strconv.ParseUint(args[1], 10, 32)
+		if parseErr != nil {
+			return fmt.Errorf("invalid version %s: %w", args[1], parseErr)
+		}
+		version, exists = uint32(parsed), true
+	}
+	if !exists {
+		return ErrNoEulaPublished
+	}
+
+	if err = c.DB.AcceptEula(account.AccountID, version); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "EULA version %d accepted for %s\n", version, account.AccountName)
+	return nil
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (c *CLI) hardwareBan(args []string) error {
+	if err := c.Hardware.Ban(args[0], strings.Join(args[1:], " "), "cli", nil); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "MacStamp %s banned\n", args[0])
+	return nil
+}
+
+func (c *CLI) hardwareUnban(args []string) error {
+	if err := c.Hardware.Unban(args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "MacStamp %s unbanned\n", args[0])
+	return nil
+}
+
+func (c *CLI) hardwareReport(args []string) error {
+	minAccounts := 2
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid account count %s: %w", args[0], err)
+		}
+		minAccounts = parsed
+	}
+
+	shared, err := c.Hardware.SharedMacStamps(minAccounts)
+	if err != nil {
+		return err
+	}
+	if len(shared) == 0 {
+		fmt.Fprintln(c.Out, "No shared MacStamps")
+		return nil
+	}
+	for _, report := range shared {
+		fmt.Fprintf(c.Out, "%s (%d accounts)\n", report.MacStamp, len(report.Accounts))
+		for _, account := range report.Accounts {
+			fmt.Fprintf(c.Out, "  %-20s %-15s %5d logins, last %s\n", account.AccountName, account.IP,
+				account.Logins, account.LastSeenAt.Format(time.DateTime))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const allServers = "all"
+
+// parseServers parses "all" or a comma separated list of server indexes.
+func parseServers(value string) ([]uint32, bool, error) {
+	if value == allServers {
+		return nil, true, nil
+	}
+
+	var servers []uint32
+	for _, part := range strings.Split(value, ",") {
+		serverIdx, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid server index %s: %w", part, err)
+		}
+		servers = append(servers, uint32(serverIdx))
+	}
+	return servers, false, nil
+}
+
+func (c *CLI) maintenanceOn(args []string) error {
+	servers, all, err := parseServers(args[0])
+	if err != nil {
+		return err
+	}
+	message := strings.Join(args[1:], " ")
+
+	if all {
+		if err = c.DB.StartMaintenance(true, 0, message); err != nil {
+			return err
+		}
+		fmt.Fprintln(c.Out, "Maintenance started for all servers")
+		return nil
+	}
+	for _, serverIdx := range servers {
+		if err = c.DB.StartMaintenance(false, serverIdx, message); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Out, "Maintenance started for server %d\n", serverIdx)
+	}
+	return nil
+}
+
+func (c *CLI) maintenanceOff(args []string) error {
+	servers, all, err := parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	if all {
+		if err = c.DB.StopAllMaintenances(); err != nil {
+			return err
+		}
+		fmt.Fprintln(c.Out, "All maintenances stopped")
+		return nil
+	}
+	for _, serverIdx := range servers {
+		if err = c.DB.StopMaintenance(false, serverIdx); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Out, "Maintenance stopped for server %d\n", serverIdx)
+	}
+	return nil
+}
+
+func (c *CLI) maintenanceStatus(_ []string) error {
+	maintenances, err := c.DB.GetMaintenances()
+	if err != nil {
+		return err
+	}
+	if c.Config.Maintenance.Enabled {
+		fmt.Fprintf(c.Out, "Config: maintenance enabled for servers %v (empty for all)\n", c.Config.Maintenance.ServerIdx)
+	}
+	if len(maintenances) == 0 {
+		fmt.Fprintln(c.Out, "No maintenance started via command")
+	}
+	for _, maintenance := range maintenances {
+		scope := fmt.Sprintf("server %d", maintenance.ServerIdx)
+		if maintenance.AllServers {
+			scope = "all servers"
+		}
+		fmt.Fprintf(c.Out, "%s since %s: %s\n",
+			scope, maintenance.CreatedAt.Format("2006-01-02 15:04:05"), maintenance.Message)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const allAccounts = "all"
+
+func parseDays(value string) (time.Duration, error) {
+	days, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number of days %s: %w", value, err)
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+func (c *CLI) premiumGrant(args []string) error {
+	duration, err := parseDays(args[1])
+	if err != nil {
+		return err
+	}
+	entitlement, err := c.Premium.Grant(args[0], duration, strings.Join(args[2:], " "))
+	if err != nil {
+		return err
+	}
+	if entitlement.EndsAt == nil {
+		fmt.Fprintf(c.Out, "Premium granted to %s permanently\n", args[0])
+		return nil
+	}
+	fmt.Fprintf(c.Out, "Premium granted to %s until %s\n", args[0], entitlement.EndsAt.Format(time.DateTime))
+	return nil
+}
+
+func (c *CLI) premiumRevoke(args []string) error {
+	if err := c.Premium.Revoke(args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Premium of %s revoked\n", args[0])
+	return nil
+}
+
+func (c *CLI) eventCodeAdd(args []string) error {
+	name := args[0]
+	if name == allAccounts {
+		name = ""
+	}
+	code, err := strconv.ParseUint(args[1], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid event code %s: %w", args[1], err)
+	}
+
+	startsAt := time.Now()
+	var endsAt *time.Time
+	if len(args) > 2 {
+		duration, parseErr := parseDays(args[2])
+		if parseErr != nil {
+			return parseErr
+		}
+		if duration > 0 {
+			end := startsAt.Add(duration)
+			endsAt = &end
+		}
+	}
+
+	eventCode, err := c.Premium.AddEventCode(name, uint32(code), strings.Join(args[min(len(args), 3):], " "),
+		startsAt, endsAt)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Event code %d added with ID %d\n", eventCode.Code, eventCode.ID)
+	return nil
+}
+
+func (c *CLI) eventCodeRemove(args []string) error {
+	id, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid ID %s: %w", args[0], err)
+	}
+	if err = c.Premium.RemoveEventCode(uint32(id)); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Event code %d removed\n", id)
+	return nil
+}
+
+func (c *CLI) eventCodeList(_ []string) error {
+	eventCodes, err := c.Premium.EventCodes()
+	if err != nil {
+		return err
+	}
+	if len(eventCodes) == 0 {
+		fmt.Fprintln(c.Out, "No active event codes")
+		return nil
+	}
+	for _, eventCode := range eventCodes {
+		target := allAccounts
+		if eventCode.AccountID != 0 {
+			target = fmt.Sprintf("account %d", eventCode.AccountID)
+		}
+		until := "permanent"
+		if eventCode.EndsAt != nil {
+			until = "until " + eventCode.EndsAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(c.Out, "  #%-5d code %-8d %-16s %-20s %s\n", eventCode.ID, eventCode.Code, target, until,
+			eventCode.Campaign)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"mononoke-go/model"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownServerSetting = errors.New("unknown server setting")
+	ErrServerNotFound       = errors.New("server has no database entry")
+)
+
+func parseServerIdx(value string) (uint32, error) {
+	serverIdx, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid server index %s: %w", value, err)
+	}
+	return uint32(serverIdx), nil
+}
+
+// serverSet changes the database entry of a server, a new entry starts with the configured settings.
+func (c *CLI) serverSet(args []string) error {
+	serverIdx, err := parseServerIdx(args[0])
+	if err != nil {
+		return err
+	}
+
+	server, exists := c.DB.GetGameServer(serverIdx)
+	if !exists {
+		configured, _ := c.Config.GetGameServer(serverIdx)
+		server = &model.GameServers{
+			ServerIdx:     serverIdx,
+			Name:          configured.Name,
+			IP:            configured.IP,
+			Port:          configured.Port,
+			ScreenshotURL: configured.ScreenshotURL,
+			AdultServer:   configured.AdultServer,
+			DisplayOrder:  configured.DisplayOrder,
+			Hidden:        configured.Hidden,
+			StaffOnly:     configured.StaffOnly,
+		}
+	}
+
+	for _, setting := range args[1:] {
+		key, value, _ := strings.Cut(setting, "=")
+		if err = setServerValue(server, key, value); err != nil {
+			return err
+		}
+	}
+	if err = c.DB.SaveGameServer(server); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Server %d saved\n", serverIdx)
+	return nil
+}
+
+func setServerValue(server *model.GameServers, key, value string) error {
+	var err error
+	switch key {
+	case "name":
+		server.Name = value
+	case "ip":
+		server.IP = value
+	case "port":
+		var port int64
+		port, err = strconv.ParseInt(value, 10, 32)
+		server.Port = int32(port)
+	case "screenshot":
+		server.ScreenshotURL = value
+	case "adult":
+		if value == "" {
+			server.AdultServer = nil
+			return nil
+		}
+		var adult bool
+		adult, err = strconv.ParseBool(value)
+		server.AdultServer = &adult
+	case "order":
+		var order int64
+		order, err = strconv.ParseInt(value, 10, 32)
+		server.DisplayOrder = int32(order)
+	case "hidden":
+		server.Hidden, err = strconv.ParseBool(value)
+	case "staffonly":
+		server.StaffOnly, err = strconv.ParseBool(value)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownServerSetting, key)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %s for %s: %w", value, key, err)
+	}
+	return nil
+}
+
+func (c *CLI) serverRemove(args []string) error {
+	serverIdx, err := parseServerIdx(args[0])
+	if err != nil {
+		return err
+	}
+	deleted, err := c.DB.DeleteGameServer(serverIdx)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %d", ErrServerNotFound, serverIdx)
+	}
+	fmt.Fprintf(c.Out, "Database entry of server %d removed\n", serverIdx)
+	return nil
+}
+
+func (c *CLI) serverList(_ []string) error {
+	servers, err := c.DB.GetGameServers()
+	if err != nil {
+		return err
+	}
+	if len(servers) == 0 {
+		fmt.Fprintln(c.Out, "No servers in the database")
+		return nil
+	}
+	for _, server := range servers {
+		adult := "reported"
+		if server.AdultServer != nil {
+			adult = strconv.FormatBool(*server.AdultServer)
+		}
+		fmt.Fprintf(c.Out, "  %-4d name=%q ip=%s port=%d order=%d adult=%s hidden=%t staffonly=%t screenshot=%s\n",
+			server.ServerIdx, server.Name, server.IP, server.Port, server.DisplayOrder, adult, server.Hidden,
+			server.StaffOnly, server.ScreenshotURL)
+	}
+	return nil
+}
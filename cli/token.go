@@ -0,0 +1,35 @@
+package cli
+
+import "fmt"
+
+func (c *CLI) verificationSend(args []string) error {
+	if err := c.Accounts.SendVerification(args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Verification mail sent to %s\n", args[0])
+	return nil
+}
+
+func (c *CLI) verificationUse(args []string) error {
+	if err := c.Accounts.VerifyEmail(args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.Out, "Email verified")
+	return nil
+}
+
+func (c *CLI) passwordResetRequest(args []string) error {
+	if err := c.Accounts.RequestPasswordReset(args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Password reset mail sent to %s\n", args[0])
+	return nil
+}
+
+func (c *CLI) passwordReset(args []string) error {
+	if err := c.Accounts.ResetPassword(args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.Out, "Password changed")
+	return nil
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"mononoke-go/utils"
+)
+
+const recoveryCodeCount = 10
+
+func (c *CLI) totpEnroll(args []string) error {
+	account, err := c.account(args[0])
+	if err != nil {
+		return err
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, codeErr := utils.GenerateRecoveryCode()
+		if codeErr != nil {
+			return codeErr
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, utils.HashRecoveryCode(code))
+	}
+
+	if err = c.DB.EnableTOTP(account.AccountID, secret, hashes); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "TOTP enabled for %s\n", account.AccountName)
+	fmt.Fprintf(c.Out, "Secret: %s\n", secret)
+	fmt.Fprintf(c.Out, "URI:    %s\n", utils.TOTPURI(c.Config.Security.TOTP.Issuer, account.AccountName, secret))
+	fmt.Fprintln(c.Out, "Append the current code or one of the recovery codes to the password when logging in.")
+	fmt.Fprintln(c.Out, "Recovery codes (each can be used once):")
+	for _, code := range codes {
+		fmt.Fprintf(c.Out, "  %s\n", code)
+	}
+	return nil
+}
+
+func (c *CLI) totpReset(args []string) error {
+	account, err := c.account(args[0])
+	if err != nil {
+		return err
+	}
+	if err = c.DB.ResetTOTP(account.AccountID); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "TOTP removed for %s\n", account.AccountName)
+	return nil
+}
@@ -7,6 +7,23 @@ import (
 	"github.com/jinzhu/configor"
 )
 
+// GameServer holds the settings of a single game server, identified by its ServerIdx.
+type GameServer struct {
+	ServerIdx     uint32
+	Capacity      uint32
+	MinAge        uint8
+	AllowedIPs    []string
+	Secret        string
+	Name          string
+	IP            string
+	Port          int32
+	ScreenshotURL string
+	AdultServer   *bool
+	DisplayOrder  int32
+	Hidden        bool
+	StaffOnly     bool
+}
+
 type Configuration struct {
 	Database struct {
 		Dialect              string `default:"sqlite3"`
@@ -26,18 +43,150 @@ type Configuration struct {
 			EncryptionKey string `default:""`
 		}
 		AuthGame struct {
-			ListenIP      string `default:"127.0.0.1"`
-			ListenPort    int32  `default:"4502"`
-			UseEncryption bool   `default:"false"`
-			EncryptionKey string `default:""`
+			ListenIP              string `default:"127.0.0.1"`
+			ListenPort            int32  `default:"4502"`
+			UseEncryption         bool   `default:"false"`
+			EncryptionKey         string `default:""`
+			AcceptLoadReports     bool   `default:"false"`
+			RequireRegistration   bool   `default:"false"`
+			KeepAliveSeconds      uint32 `default:"30"`
+			HeartbeatSeconds      uint32 `default:"0"`
+			HeartbeatTimeout      uint32 `default:"90"`
+			ReconnectGraceSeconds uint32 `default:"0"`
+		}
+		DuplicateLogin struct {
+			Policy             string `default:"kick"`
+			KickTimeoutSeconds uint32 `default:"10"`
 		}
 		DefaultDESKey  string `default:""`
 		AgeRestriction uint8  `default:"18"`
+		HideAgeLimited bool   `default:"false"`
+	}
+	Security struct {
+		Lockout struct {
+			Enabled            bool   `default:"true"`
+			WindowSeconds      uint32 `default:"900"`
+			AccountMaxFailures uint32 `default:"5"`
+			IPMaxFailures      uint32 `default:"20"`
+			LockSeconds        uint32 `default:"60"`
+			MaxLockSeconds     uint32 `default:"3600"`
+			DecaySeconds       uint32 `default:"86400"`
+		}
+		SecurityCode struct {
+			MaxFailures uint32 `default:"5"`
+			LockSeconds uint32 `default:"900"`
+		}
+		TOTP struct {
+			Issuer            string `default:"mononoke-go"`
+			EnforcePermission uint32 `default:"0"`
+		}
+		// Defaults are parsed as YAML, the messages have to be quoted because of the colon.
+		AccountBan struct {
+			Message          string `default:"'Your account is banned until {until}. Reason: {reason}'"`
+			PermanentMessage string `default:"'Your account is banned permanently. Reason: {reason}'"`
+			DateFormat       string `default:"2006-01-02 15:04 MST"`
+		}
+		BanList struct {
+			File          string `default:""`
+			ReloadSeconds uint32 `default:"60"`
+		}
+		OneTimeKey struct {
+			ExpirySeconds uint32 `default:"60"`
+			BindIP        bool   `default:"false"`
+		}
+	}
+	GameServers []GameServer
+	ServerList  struct {
+		ShowOffline      bool   `default:"false"`
+		OfflineUserRatio uint16 `default:"65535"`
+		StaffPermission  uint32 `default:"100"`
+		ReloadSeconds    uint32 `default:"60"`
+	}
+	Queue struct {
+		DefaultCapacity    uint32 `default:"0"`
+		SecondsPerPlayer   uint32 `default:"30"`
+		PriorityPermission uint32 `default:"100"`
+		TimeoutSeconds     uint32 `default:"60"`
+	}
+	Maintenance struct {
+		Enabled          bool `default:"false"`
+		ServerIdx        []uint32
+		BypassPermission uint32 `default:"100"`
+		Message          string `default:"The server is under maintenance, please try again later."`
+	}
+	Fatigue struct {
+		Enabled          bool   `default:"false"`
+		MaxAge           uint8  `default:"18"`
+		ExemptPermission uint32 `default:"0"`
+		TiredMinutes     uint32 `default:"180"`
+		HarmfulMinutes   uint32 `default:"300"`
+		ResetMinutes     uint32 `default:"300"`
+		RejectWhenTired  bool   `default:"false"`
+	}
+	Account struct {
+		RequireEmailVerification bool   `default:"false"`
+		VerifyTokenHours         uint32 `default:"48"`
+		ResetTokenMinutes        uint32 `default:"60"`
+		VerifyURL                string `default:""`
+		ResetURL                 string `default:""`
+		UnverifiedMessage        string `default:"Please verify your email address first."`
+	}
+	Auth struct {
+		Backend string `default:"sql"`
+		File    string `default:"data/accounts.json"`
+		HTTP    struct {
+			URL            string `default:""`
+			Token          string `default:""`
+			TimeoutSeconds uint32 `default:"5"`
+		}
+	}
+	PCBang struct {
+		Networks []string
+	}
+	Launcher struct {
+		Enabled      bool   `default:"false"`
+		TokenSeconds uint32 `default:"60"`
+	}
+	Mail struct {
+		Sender    string `default:"none"`
+		From      string `default:"mononoke-go <noreply@localhost>"`
+		Directory string `default:"data/mail"`
+		SMTP      struct {
+			Host     string `default:"localhost"`
+			Port     int    `default:"587"`
+			Username string `default:""`
+			Password string `default:""`
+		}
+	}
+	Admin struct {
+		ListenAddr    string `default:""`
+		Token         string `default:""`
+		LauncherToken string `default:""`
+	}
+	Eula struct {
+		RequireAcceptance bool `default:"false"`
+	}
+	Audit struct {
+		Enabled       bool   `default:"true"`
+		BatchSize     uint32 `default:"100"`
+		QueueSize     uint32 `default:"10000"`
+		FlushSeconds  uint32 `default:"5"`
+		RetentionDays uint32 `default:"90"`
 	}
 	LoggerLevel string `default:"Info"`
 	LoggerType  string `default:"Text"`
 }
 
+// GetGameServer returns the settings of a game server, false if it isn't configured.
+func (c *Configuration) GetGameServer(serverIdx uint32) (GameServer, bool) {
+	for _, server := range c.GameServers {
+		if server.ServerIdx == serverIdx {
+			return server, true
+		}
+	}
+	return GameServer{ServerIdx: serverIdx}, false
+}
+
 // Get returns the configuration extracted from env variables or config file.
 func Get() *Configuration {
 	conf := new(Configuration)
@@ -2,9 +2,12 @@ package database
 
 import (
 	"errors"
+	"fmt"
 	"mononoke-go/config"
 	"mononoke-go/model"
 	"mononoke-go/utils"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -50,3 +53,170 @@ func (d *GormDatabase) UpdateLastLoginServerIdx(accountID, lastLoginServerIdx ui
 	}
 	return true, nil
 }
+
+func (d *GormDatabase) GetUserByName(name string) (*model.Accounts, bool) {
+	account := new(model.Accounts)
+	result := d.DB.Where("account_name = ?", name).Limit(1).Find(account)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, false
+	}
+	return account, true
+}
+
+func (d *GormDatabase) GetUserByID(accountID uint32) (*model.Accounts, bool) {
+	account := new(model.Accounts)
+	result := d.DB.Where("account_id = ?", accountID).Limit(1).Find(account)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, false
+	}
+	return account, true
+}
+
+func (d *GormDatabase) CreateAccount(account *model.Accounts) error {
+	return d.DB.Create(account).Error
+}
+
+// AccountNameExists checks case-insensitively if the account name is already used.
+func (d *GormDatabase) AccountNameExists(name string) bool {
+	count := int64(0)
+	d.DB.Model(model.Accounts{}).Where("LOWER(account_name) = LOWER(?)", name).Count(&count)
+	return count > 0
+}
+
+// EmailExists checks case-insensitively if the email is used by another account than exceptAccountID.
+func (d *GormDatabase) EmailExists(email string, exceptAccountID uint32) bool {
+	count := int64(0)
+	d.DB.Model(model.Accounts{}).
+		Where("LOWER(email) = LOWER(?) AND account_id <> ?", email, exceptAccountID).
+		Count(&count)
+	return count > 0
+}
+
+func (d *GormDatabase) UpdatePassword(accountID uint32, password string) error {
+	return d.DB.Model(model.Accounts{}).
+		Where("account_id", accountID).
+		Update("password", password).Error
+}
+
+func (d *GormDatabase) UpdateEmail(accountID uint32, email string) error {
+	return d.DB.Model(model.Accounts{}).
+		Where("account_id", accountID).
+		Update("email", email).Error
+}
+
+func (d *GormDatabase) SetBlocked(accountID uint32, blocked bool) error {
+	return d.DB.Model(model.Accounts{}).
+		Where("account_id", accountID).
+		Update("blocked", blocked).Error
+}
+
+func (d *GormDatabase) SetPermission(accountID, permission uint32) error {
+	return d.DB.Model(model.Accounts{}).
+		Where("account_id", accountID).
+		Update("permission", permission).Error
+}
+
+// DeleteAccount removes an account together with everything stored for it, tokens, bans, entitlements,
+// MacStamps and its lockout. Audit entries are kept.
+func (d *GormDatabase) DeleteAccount(account *model.Accounts) error {
+	return d.DB.Transaction(func(tx *gorm.DB) error {
+		for _, related := range []interface{}{
+			new(model.RecoveryCodes),
+			new(model.EulaAcceptances),
+			new(model.AccountBans),
+			new(model.AccountTokens),
+			new(model.PremiumEntitlements),
+			new(model.EventCodes),
+			new(model.MacStamps),
+		} {
+			if err := tx.Where("account_id = ?", account.AccountID).Delete(related).Error; err != nil {
+				return err
+			}
+		}
+		err := tx.Where("kind = ? AND subject = ?", model.LockoutKindAccount, account.AccountName).
+			Delete(new(model.LoginLockouts)).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("account_id = ?", account.AccountID).Delete(new(model.Accounts)).Error
+	})
+}
+
+func (d *GormDatabase) SetBirthdate(accountID uint32, birthdate time.Time) error {
+	return d.DB.Model(model.Accounts{}).
+		Where("account_id", accountID).
+		Update("birthdate", birthdate).Error
+}
+
+func (d *GormDatabase) SetFatigueLimit(accountID uint32, limit *bool) error {
+	return d.DB.Model(model.Accounts{}).
+		Where("account_id", accountID).
+		Update("fatigue_limit", limit).Error
+}
+
+func (d *GormDatabase) UpdatePlayTime(accountID, sessionTime, continuousPlayTime uint32, logoutAt time.Time) error {
+	return d.DB.Model(model.Accounts{}).
+		Where("account_id", accountID).
+		Updates(map[string]interface{}{
+			"play_time":            gorm.Expr("play_time + ?", sessionTime),
+			"continuous_play_time": continuousPlayTime,
+			"last_logout_at":       logoutAt,
+		}).Error
+}
+
+func (d *GormDatabase) SetSecurityCode(accountID uint32, securityCode string) error {
+	return d.DB.Model(model.Accounts{}).
+		Where("account_id", accountID).
+		Updates(map[string]interface{}{
+			"security_code":              securityCode,
+			"security_code_failures":     0,
+			"security_code_locked_until": nil,
+		}).Error
+}
+
+func (d *GormDatabase) UpdateSecurityCodeFailures(accountID, failures uint32, lockedUntil *time.Time) error {
+	return d.DB.Model(model.Accounts{}).
+		Where("account_id", accountID).
+		Updates(map[string]interface{}{
+			"security_code_failures":     failures,
+			"security_code_locked_until": lockedUntil,
+		}).Error
+}
+
+var ErrAccountConflict = errors.New("external account conflicts with a local account")
+
+// SyncAccount stores an account verified by an external authenticator under its external ID.
+// Existing accounts keep their local data like TOTP settings and only get the external fields updated.
+// Local accounts with the same ID but another name, or the same name or email but another ID, are never changed.
+func (d *GormDatabase) SyncAccount(external *model.Accounts) (*model.Accounts, error) {
+	if external.Email != "" && d.EmailExists(external.Email, external.AccountID) {
+		return nil, fmt.Errorf("%w: email of %s is used by another account", ErrAccountConflict, external.AccountName)
+	}
+
+	account := new(model.Accounts)
+	result := d.DB.Where("account_id = ?", external.AccountID).Limit(1).Find(account)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		if d.AccountNameExists(external.AccountName) {
+			return nil, fmt.Errorf("%w: name %s is used by another account", ErrAccountConflict, external.AccountName)
+		}
+		if err := d.DB.Create(external).Error; err != nil {
+			return nil, err
+		}
+		return external, nil
+	}
+	if !strings.EqualFold(account.AccountName, external.AccountName) {
+		return nil, fmt.Errorf("%w: ID %d belongs to %s, not %s", ErrAccountConflict,
+			external.AccountID, account.AccountName, external.AccountName)
+	}
+
+	account.AccountName = external.AccountName
+	account.Email = external.Email
+	account.Age = external.Age
+	account.Permission = external.Permission
+	account.Blocked = external.Blocked
+	err := d.DB.Model(account).Select("account_name", "email", "age", "permission", "blocked").Updates(account).Error
+	return account, err
+}
@@ -0,0 +1,18 @@
+package database
+
+import (
+	"mononoke-go/model"
+	"time"
+)
+
+func (d *GormDatabase) InsertLoginAudits(entries []model.LoginAudits) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return d.DB.CreateInBatches(entries, len(entries)).Error
+}
+
+func (d *GormDatabase) DeleteLoginAuditsBefore(before time.Time) (int64, error) {
+	result := d.DB.Where("created_at < ?", before).Delete(new(model.LoginAudits))
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"mononoke-go/model"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+func (d *GormDatabase) GetActiveIPBans() ([]model.IPBans, error) {
+	var bans []model.IPBans
+	err := d.DB.Where("expires_at IS NULL OR expires_at > ?", time.Now()).Find(&bans).Error
+	if err != nil {
+		return nil, err
+	}
+	return bans, nil
+}
+
+// SaveIPBan creates the ban or replaces an existing ban of the same network.
+func (d *GormDatabase) SaveIPBan(ban *model.IPBans) error {
+	return d.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "network"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "created_by", "created_at", "expires_at"}),
+	}).Create(ban).Error
+}
+
+func (d *GormDatabase) DeleteIPBan(network string) (bool, error) {
+	result := d.DB.Where("network = ?", network).Delete(new(model.IPBans))
+	return result.RowsAffected > 0, result.Error
+}
+
+func (d *GormDatabase) AddAccountBan(ban *model.AccountBans) error {
+	return d.DB.Create(ban).Error
+}
+
+// LiftAccountBans ends the active bans of the account, they stay in the ban history.
+func (d *GormDatabase) LiftAccountBans(accountID uint32) (int64, error) {
+	now := time.Now()
+	result := d.DB.Model(model.AccountBans{}).
+		Where("account_id = ? AND (ends_at IS NULL OR ends_at > ?)", accountID, now).
+		Update("ends_at", now)
+	return result.RowsAffected, result.Error
+}
+
+func (d *GormDatabase) GetAccountBans(accountID uint32) ([]model.AccountBans, error) {
+	var bans []model.AccountBans
+	err := d.DB.Where("account_id = ?", accountID).Order("starts_at").Find(&bans).Error
+	return bans, err
+}
+
+// GetActiveAccountBan returns the currently active ban of an account which lasts the longest.
+func (d *GormDatabase) GetActiveAccountBan(accountID uint32) (*model.AccountBans, bool) {
+	var bans []model.AccountBans
+	now := time.Now()
+	err := d.DB.Where("account_id = ? AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", accountID, now, now).
+		Find(&bans).Error
+	if err != nil || len(bans) == 0 {
+		return nil, false
+	}
+
+	active := &bans[0]
+	for i := range bans {
+		ban := &bans[i]
+		if active.EndsAt != nil && (ban.EndsAt == nil || ban.EndsAt.After(*active.EndsAt)) {
+			active = ban
+		}
+	}
+	return active, true
+}
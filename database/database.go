@@ -89,7 +89,22 @@ func New(dial, conn, defaultUser, defaultPass string, createDefault bool) (*Gorm
 		database.SetConnMaxLifetime(9 * time.Minute)
 	}
 
-	if err = db.AutoMigrate(new(model.Accounts)); err != nil {
+	if err = db.AutoMigrate(
+		new(model.Accounts),
+		new(model.LoginLockouts),
+		new(model.IPBans),
+		new(model.AccountBans),
+		new(model.LoginAudits),
+		new(model.RecoveryCodes),
+		new(model.EulaVersions),
+		new(model.EulaAcceptances),
+		new(model.Maintenances),
+		new(model.AccountTokens),
+		new(model.MacStamps),
+		new(model.HardwareBans),
+		new(model.PremiumEntitlements),
+		new(model.EventCodes),
+		new(model.GameServers)); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"mononoke-go/model"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// GetCurrentEulaVersion returns the latest published EULA version, false if there is none.
+func (d *GormDatabase) GetCurrentEulaVersion() (uint32, bool) {
+	eula := new(model.EulaVersions)
+	result := d.DB.Where("published_at <= ?", time.Now()).Order("version DESC").Limit(1).Find(eula)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return 0, false
+	}
+	return eula.Version, true
+}
+
+func (d *GormDatabase) PublishEulaVersion(version uint32, description string, publishedAt time.Time) error {
+	return d.DB.Save(&model.EulaVersions{
+		Version:     version,
+		Description: description,
+		PublishedAt: publishedAt,
+	}).Error
+}
+
+func (d *GormDatabase) HasAcceptedEula(accountID, version uint32) bool {
+	count := int64(0)
+	d.DB.Model(model.EulaAcceptances{}).
+		Where("account_id = ? AND version = ?", accountID, version).
+		Count(&count)
+	return count > 0
+}
+
+func (d *GormDatabase) AcceptEula(accountID, version uint32) error {
+	return d.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.EulaAcceptances{
+		AccountID:  accountID,
+		Version:    version,
+		AcceptedAt: time.Now(),
+	}).Error
+}
@@ -0,0 +1,29 @@
+package database
+
+import "mononoke-go/model"
+
+func (d *GormDatabase) GetGameServers() ([]model.GameServers, error) {
+	var servers []model.GameServers
+	if err := d.DB.Order("server_idx").Find(&servers).Error; err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+func (d *GormDatabase) GetGameServer(serverIdx uint32) (*model.GameServers, bool) {
+	server := new(model.GameServers)
+	result := d.DB.Where("server_idx = ?", serverIdx).Limit(1).Find(server)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, false
+	}
+	return server, true
+}
+
+func (d *GormDatabase) SaveGameServer(server *model.GameServers) error {
+	return d.DB.Save(server).Error
+}
+
+func (d *GormDatabase) DeleteGameServer(serverIdx uint32) (bool, error) {
+	result := d.DB.Where("server_idx = ?", serverIdx).Delete(new(model.GameServers))
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"mononoke-go/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecordMacStamp stores that the account logged in from the machine, repeated logins update the existing entry.
+func (d *GormDatabase) RecordMacStamp(accountID uint32, macStamp, ip string, now time.Time) error {
+	return d.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "account_id"}, {Name: "mac_stamp"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"ip":           ip,
+			"logins":       gorm.Expr("logins + 1"),
+			"last_seen_at": now,
+		}),
+	}).Create(&model.MacStamps{
+		AccountID:   accountID,
+		MacStamp:    macStamp,
+		IP:          ip,
+		Logins:      1,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}).Error
+}
+
+func (d *GormDatabase) GetActiveHardwareBan(macStamp string) (*model.HardwareBans, bool) {
+	ban := new(model.HardwareBans)
+	result := d.DB.Where("mac_stamp = ? AND (expires_at IS NULL OR expires_at > ?)", macStamp, time.Now()).
+		Limit(1).Find(ban)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, false
+	}
+	return ban, true
+}
+
+// SaveHardwareBan creates the ban or replaces an existing ban of the same MacStamp.
+func (d *GormDatabase) SaveHardwareBan(ban *model.HardwareBans) error {
+	return d.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "mac_stamp"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "created_by", "created_at", "expires_at"}),
+	}).Create(ban).Error
+}
+
+func (d *GormDatabase) DeleteHardwareBan(macStamp string) (bool, error) {
+	result := d.DB.Where("mac_stamp = ?", macStamp).Delete(new(model.HardwareBans))
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetSharedMacStamps returns the MacStamps used by at least minAccounts different accounts.
+func (d *GormDatabase) GetSharedMacStamps(minAccounts int) ([]string, error) {
+	var macStamps []string
+	err := d.DB.Model(model.MacStamps{}).
+		Select("mac_stamp").
+		Group("mac_stamp").
+		Having("COUNT(DISTINCT account_id) >= ?", minAccounts).
+		Order("mac_stamp").
+		Pluck("mac_stamp", &macStamps).Error
+	return macStamps, err
+}
+
+// GetMacStampAccounts returns the accounts which logged in from one of the MacStamps.
+func (d *GormDatabase) GetMacStampAccounts(macStamps []string) ([]model.MacStamps, []model.Accounts, error) {
+	var entries []model.MacStamps
+	if err := d.DB.Where("mac_stamp IN ?", macStamps).Order("last_seen_at DESC").Find(&entries).Error; err != nil {
+		return nil, nil, err
+	}
+
+	accountIDs := make([]uint32, 0, len(entries))
+	for _, entry := range entries {
+		accountIDs = append(accountIDs, entry.AccountID)
+	}
+	var accounts []model.Accounts
+	if len(accountIDs) > 0 {
+		if err := d.DB.Where("account_id IN ?", accountIDs).Find(&accounts).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+	return entries, accounts, nil
+}
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"mononoke-go/model"
+)
+
+func (d *GormDatabase) GetLoginLockout(kind, subject string) (*model.LoginLockouts, bool) {
+	lockout := new(model.LoginLockouts)
+	result := d.DB.Where("kind = ? AND subject = ?", kind, subject).Limit(1).Find(lockout)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, false
+	}
+	return lockout, true
+}
+
+func (d *GormDatabase) SaveLoginLockout(lockout *model.LoginLockouts) error {
+	return d.DB.Save(lockout).Error
+}
+
+func (d *GormDatabase) ResetLoginLockout(kind, subject string) error {
+	return d.DB.Where("kind = ? AND subject = ?", kind, subject).
+		Delete(new(model.LoginLockouts)).Error
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"mononoke-go/model"
+)
+
+func (d *GormDatabase) GetMaintenances() ([]model.Maintenances, error) {
+	var maintenances []model.Maintenances
+	if err := d.DB.Find(&maintenances).Error; err != nil {
+		return nil, err
+	}
+	return maintenances, nil
+}
+
+// StartMaintenance starts a maintenance for all servers or a single server, replacing an existing one.
+func (d *GormDatabase) StartMaintenance(allServers bool, serverIdx uint32, message string) error {
+	if err := d.StopMaintenance(allServers, serverIdx); err != nil {
+		return err
+	}
+	return d.DB.Create(&model.Maintenances{
+		AllServers: allServers,
+		ServerIdx:  serverIdx,
+		Message:    message,
+	}).Error
+}
+
+// StopMaintenance stops the maintenance of all servers or of a single server.
+func (d *GormDatabase) StopMaintenance(allServers bool, serverIdx uint32) error {
+	query := d.DB.Where("all_servers = ?", true)
+	if !allServers {
+		query = d.DB.Where("all_servers = ? AND server_idx = ?", false, serverIdx)
+	}
+	return query.Delete(new(model.Maintenances)).Error
+}
+
+func (d *GormDatabase) StopAllMaintenances() error {
+	return d.DB.Where("1 = 1").Delete(new(model.Maintenances)).Error
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"mononoke-go/model"
+	"time"
+)
+
+func (d *GormDatabase) HasActivePremium(accountID uint32) bool {
+	count := int64(0)
+	now := time.Now()
+	d.DB.Model(model.PremiumEntitlements{}).
+		Where("account_id = ? AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", accountID, now, now).
+		Count(&count)
+	return count > 0
+}
+
+func (d *GormDatabase) AddPremium(entitlement *model.PremiumEntitlements) error {
+	return d.DB.Create(entitlement).Error
+}
+
+// RevokePremium ends all active entitlements of an account now.
+func (d *GormDatabase) RevokePremium(accountID uint32) (int64, error) {
+	now := time.Now()
+	result := d.DB.Model(model.PremiumEntitlements{}).
+		Where("account_id = ? AND (ends_at IS NULL OR ends_at > ?)", accountID, now).
+		Update("ends_at", now)
+	return result.RowsAffected, result.Error
+}
+
+// GetActiveEventCode returns the newest active event code of the account, or of a campaign if the account has none.
+func (d *GormDatabase) GetActiveEventCode(accountID uint32) (uint32, bool) {
+	eventCode := new(model.EventCodes)
+	now := time.Now()
+	result := d.DB.Where("account_id IN ? AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)",
+		[]uint32{accountID, 0}, now, now).
+		Order("account_id DESC, starts_at DESC").
+		Limit(1).Find(eventCode)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return 0, false
+	}
+	return eventCode.Code, true
+}
+
+func (d *GormDatabase) GetActiveEventCodes() ([]model.EventCodes, error) {
+	var eventCodes []model.EventCodes
+	err := d.DB.Where("ends_at IS NULL OR ends_at > ?", time.Now()).Order("id").Find(&eventCodes).Error
+	return eventCodes, err
+}
+
+func (d *GormDatabase) AddEventCode(eventCode *model.EventCodes) error {
+	return d.DB.Create(eventCode).Error
+}
+
+func (d *GormDatabase) DeleteEventCode(id uint32) (bool, error) {
+	result := d.DB.Where("id = ?", id).Delete(new(model.EventCodes))
+	return result.RowsAffected > 0, result.Error
+}
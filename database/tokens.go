@@ -0,0 +1,57 @@
+package database
+
+import (
+	"mononoke-go/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CreateAccountToken stores a new token and invalidates the unused tokens of the account with the same purpose.
+func (d *GormDatabase) CreateAccountToken(token *model.AccountTokens) error {
+	return d.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("account_id = ? AND purpose = ? AND used_at IS NULL", token.AccountID, token.Purpose).
+			Delete(new(model.AccountTokens)).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// UseAccountToken marks an unused and unexpired token as used and returns it.
+func (d *GormDatabase) UseAccountToken(purpose, tokenHash string, now time.Time) (*model.AccountTokens, bool) {
+	result := d.DB.Model(model.AccountTokens{}).
+		Where("purpose = ? AND token_hash = ? AND used_at IS NULL AND expires_at > ?", purpose, tokenHash, now).
+		Update("used_at", now)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, false
+	}
+
+	token := new(model.AccountTokens)
+	result = d.DB.Where("token_hash = ?", tokenHash).Limit(1).Find(token)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, false
+	}
+	return token, true
+}
+
+// UseAccountTokenOf marks an unused and unexpired token of the account as used.
+func (d *GormDatabase) UseAccountTokenOf(accountID uint32, purpose, tokenHash string, now time.Time) bool {
+	result := d.DB.Model(model.AccountTokens{}).
+		Where("account_id = ? AND purpose = ? AND token_hash = ? AND used_at IS NULL AND expires_at > ?",
+			accountID, purpose, tokenHash, now).
+		Update("used_at", now)
+	return result.Error == nil && result.RowsAffected > 0
+}
+
+// DeleteExpiredAccountTokens removes tokens which can't be used anymore.
+func (d *GormDatabase) DeleteExpiredAccountTokens(before time.Time) error {
+	return d.DB.Where("expires_at < ?", before).Delete(new(model.AccountTokens)).Error
+}
+
+func (d *GormDatabase) SetEmailVerificationPending(accountID uint32, pending bool) error {
+	return d.DB.Model(model.Accounts{}).
+		Where("account_id", accountID).
+		Update("email_verification_pending", pending).Error
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"mononoke-go/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EnableTOTP stores the TOTP secret of an account and replaces its recovery codes.
+func (d *GormDatabase) EnableTOTP(accountID uint32, secret string, recoveryHashes []string) error {
+	return d.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(model.Accounts{}).
+			Where("account_id", accountID).
+			Updates(map[string]interface{}{
+				"totp_enabled":   true,
+				"totp_secret":    secret,
+				"totp_last_step": 0,
+			}).Error
+		if err != nil {
+			return err
+		}
+		if err = tx.Where("account_id = ?", accountID).Delete(new(model.RecoveryCodes)).Error; err != nil {
+			return err
+		}
+		codes := make([]model.RecoveryCodes, 0, len(recoveryHashes))
+		for _, hash := range recoveryHashes {
+			codes = append(codes, model.RecoveryCodes{AccountID: accountID, CodeHash: hash})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// ResetTOTP removes the TOTP enrollment and all recovery codes of an account.
+func (d *GormDatabase) ResetTOTP(accountID uint32) error {
+	return d.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(model.Accounts{}).
+			Where("account_id", accountID).
+			Updates(map[string]interface{}{
+				"totp_enabled":   false,
+				"totp_secret":    "",
+				"totp_last_step": 0,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("account_id = ?", accountID).Delete(new(model.RecoveryCodes)).Error
+	})
+}
+
+func (d *GormDatabase) UpdateTOTPLastStep(accountID uint32, step int64) error {
+	return d.DB.Model(model.Accounts{}).
+		Where("account_id", accountID).
+		Update("totp_last_step", step).Error
+}
+
+func (d *GormDatabase) HasRecoveryCode(accountID uint32, codeHash string) bool {
+	count := int64(0)
+	d.DB.Model(model.RecoveryCodes{}).
+		Where("account_id = ? AND code_hash = ? AND used_at IS NULL", accountID, codeHash).
+		Count(&count)
+	return count > 0
+}
+
+// UseRecoveryCode marks a recovery code as used, returns false if it was already used.
+func (d *GormDatabase) UseRecoveryCode(accountID uint32, codeHash string) bool {
+	result := d.DB.Model(model.RecoveryCodes{}).
+		Where("account_id = ? AND code_hash = ? AND used_at IS NULL", accountID, codeHash).
+		Update("used_at", time.Now())
+	return result.Error == nil && result.RowsAffected > 0
+}
@@ -4,14 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"mononoke-go/api"
 	"mononoke-go/config"
 	"mononoke-go/database"
 	"mononoke-go/entities"
+	"mononoke-go/mail"
 	"mononoke-go/net"
 	"mononoke-go/utils"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 func Create(db *database.GormDatabase, conf *config.Configuration, log *slog.Logger) error {
@@ -24,6 +27,35 @@ func Create(db *database.GormDatabase, conf *config.Configuration, log *slog.Log
 		Games: make(map[uint32]*entities.Game),
 	}
 
+	banList := &entities.BanList{
+		DB:   db,
+		File: conf.Security.BanList.File,
+		Log:  log,
+	}
+	if err := banList.Reload(); err != nil {
+		return fmt.Errorf("error loading ban list: %w", err)
+	}
+	go reload("ban list", banList.Reload, time.Duration(conf.Security.BanList.ReloadSeconds)*time.Second, log)
+
+	registry := &entities.ServerRegistry{
+		DB:     db,
+		Config: conf,
+		Log:    log,
+	}
+	if err := registry.Reload(); err != nil {
+		return fmt.Errorf("error loading game servers: %w", err)
+	}
+	go reload("server registry", registry.Reload, time.Duration(conf.ServerList.ReloadSeconds)*time.Second, log)
+
+	authenticator, err := entities.NewAuthenticator(db, conf, log)
+	if err != nil {
+		return fmt.Errorf("error creating authenticator: %w", err)
+	}
+
+	auditLog := entities.NewAuditLog(db, conf, log)
+	go auditLog.Run()
+	defer auditLog.Close()
+
 	authClient := net.NewTCPServer(
 		fmt.Sprintf("%s:%d", conf.Server.AuthClient.ListenIP, conf.Server.AuthClient.ListenPort),
 		conf.Server.AuthClient.UseEncryption,
@@ -33,9 +65,23 @@ func Create(db *database.GormDatabase, conf *config.Configuration, log *slog.Log
 	if authClient == nil {
 		return errors.New("error starting AuthClient, stopping")
 	}
+	gameHandler := entities.GameHandler{
+		List:       gameList,
+		PlayerList: playerList,
+		DB:         db,
+		Audit:      auditLog,
+		Config:     conf,
+		Log:        log,
+	}
 	authHandler := entities.AuthHandler{
 		GameSrvs: gameList,
+		Games:    &gameHandler,
 		Players:  playerList,
+		Queue:    entities.NewLoginQueue(time.Duration(conf.Queue.TimeoutSeconds) * time.Second),
+		Bans:     banList,
+		Audit:    auditLog,
+		Auth:     authenticator,
+		Registry: registry,
 		DESKey:   utils.InitDESKey(conf.Server.DefaultDESKey),
 		DB:       db,
 		Config:   conf,
@@ -52,13 +98,13 @@ func Create(db *database.GormDatabase, conf *config.Configuration, log *slog.Log
 	if gameClient == nil {
 		return errors.New("error starting AuthClient, stopping")
 	}
-	gameHandler := entities.GameHandler{
-		List:       gameList,
-		PlayerList: playerList,
-		DB:         db,
-		Log:        log,
-	}
 	gameHandler.InitServer(gameClient)
+	gameClient.SetKeepAlive(time.Duration(conf.Server.AuthGame.KeepAliveSeconds) * time.Second)
+	if conf.Server.AuthGame.HeartbeatSeconds > 0 {
+		go gameHandler.MonitorHeartbeats(
+			time.Duration(conf.Server.AuthGame.HeartbeatSeconds)*time.Second,
+			time.Duration(conf.Server.AuthGame.HeartbeatTimeout)*time.Second)
+	}
 
 	go func() {
 		err := gameClient.Listen()
@@ -69,13 +115,59 @@ func Create(db *database.GormDatabase, conf *config.Configuration, log *slog.Log
 		doShutdown(shutdown, err)
 	}()
 
-	err := <-shutdown
+	if conf.Admin.ListenAddr != "" {
+		mailSender, err := mail.New(conf, log)
+		if err != nil {
+			return err
+		}
+		accountService := &entities.AccountService{DB: db, Config: conf, Mail: mailSender, Log: log}
+		adminAPI := api.Server{
+			Accounts:      accountService,
+			Hardware:      &entities.HardwareService{DB: db, Log: log},
+			Premium:       &entities.PremiumService{DB: db, Accounts: accountService, Log: log},
+			Bans:          &entities.BanService{DB: db, Accounts: accountService, Bans: banList, Log: log},
+			Token:         conf.Admin.Token,
+			LauncherToken: conf.Admin.LauncherToken,
+			Log:           log,
+		}
+		go func() {
+			err := adminAPI.ListenAndServe(conf.Admin.ListenAddr)
+			doShutdown(shutdown, fmt.Errorf("admin API stopped: %w", err))
+		}()
+	}
+
+	err = <-shutdown
 	log.Error("Shutting down",
 		"function", "Engine::Create",
 		"error", err.Error())
 	return err
 }
 
+// reload calls the reload function periodically and whenever SIGHUP is received.
+func reload(name string, reloadFunc func() error, interval time.Duration, log *slog.Logger) {
+	onSignal := make(chan os.Signal, 1)
+	signal.Notify(onSignal, syscall.SIGHUP)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-tick:
+		case <-onSignal:
+		}
+		if err := reloadFunc(); err != nil {
+			log.Error("Cannot reload "+name,
+				"function", "Engine::reload",
+				"error", err.Error())
+		}
+	}
+}
+
 func doShutdownOnSignal(shutdown chan<- error) {
 	onSignal := make(chan os.Signal, 1)
 	signal.Notify(onSignal, os.Interrupt, syscall.SIGTERM)
@@ -0,0 +1,208 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"mononoke-go/config"
+	"mononoke-go/database"
+	"mononoke-go/mail"
+	"mononoke-go/model"
+	"mononoke-go/utils"
+	netmail "net/mail"
+	"regexp"
+)
+
+const (
+	AccountNameMinLength = 4
+	// AccountNameMaxLength is limited by ClientAuthAccount, current clients send 56 bytes including
+	// the terminating zero. Clients before 5.2 only support 18 characters.
+	AccountNameMaxLength = 55
+	PasswordMinLength    = 6
+	// passwordMaxLength is the bcrypt limit, including the salt.
+	passwordMaxLength = 72
+	emailMaxLength    = 32
+)
+
+var (
+	ErrAccountNotFound    = errors.New("account not found")
+	ErrInvalidAccountName = fmt.Errorf("account name must have %d to %d characters (letters, digits, _ and -)",
+		AccountNameMinLength, AccountNameMaxLength)
+	ErrAccountNameTaken = errors.New("account name is already taken")
+	ErrInvalidEmail     = errors.New("invalid email address")
+	ErrEmailTaken       = errors.New("email address is already used")
+	ErrInvalidPassword  = fmt.Errorf("password must have at least %d characters", PasswordMinLength)
+)
+
+var accountNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// AccountService manages accounts for the admin API and the CLI.
+type AccountService struct {
+	DB     *database.GormDatabase
+	Config *config.Configuration
+	Mail   mail.Sender
+	Log    *slog.Logger
+}
+
+func (s *AccountService) Get(name string) (*model.Accounts, error) {
+	account, found := s.DB.GetUserByName(name)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, name)
+	}
+	return account, nil
+}
+
+func (s *AccountService) Create(name, password, email string, permission uint32) (*model.Accounts, error) {
+	if len(name) < AccountNameMinLength || len(name) > AccountNameMaxLength || !accountNamePattern.MatchString(name) {
+		return nil, ErrInvalidAccountName
+	}
+	if s.DB.AccountNameExists(name) {
+		return nil, ErrAccountNameTaken
+	}
+	if err := s.checkEmail(email, 0); err != nil {
+		return nil, err
+	}
+	verify := s.Config.Account.RequireEmailVerification
+	if verify && email == "" {
+		return nil, ErrEmailRequired
+	}
+	hash, err := s.hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &model.Accounts{
+		AccountName:              name,
+		Password:                 hash,
+		Email:                    email,
+		Permission:               permission,
+		EmailVerificationPending: verify,
+	}
+	if err = s.DB.CreateAccount(account); err != nil {
+		return nil, err
+	}
+	s.Log.Info("Account created",
+		"function", "AccountService::Create",
+		"accountID", account.AccountID,
+		"accountName", account.AccountName)
+
+	if verify {
+		s.sendVerificationOrLog(account)
+	}
+	return account, nil
+}
+
+func (s *AccountService) ChangePassword(name, password string) error {
+	account, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	hash, err := s.hashPassword(password)
+	if err != nil {
+		return err
+	}
+	return s.logChange(account, "password", s.DB.UpdatePassword(account.AccountID, hash))
+}
+
+func (s *AccountService) ChangeEmail(name, email string) error {
+	account, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	if err = s.checkEmail(email, account.AccountID); err != nil {
+		return err
+	}
+	verify := s.Config.Account.RequireEmailVerification
+	if verify && email == "" {
+		return ErrEmailRequired
+	}
+	if err = s.logChange(account, "email", s.DB.UpdateEmail(account.AccountID, email)); err != nil || !verify {
+		return err
+	}
+
+	if err = s.DB.SetEmailVerificationPending(account.AccountID, true); err != nil {
+		return err
+	}
+	account.Email = email
+	s.sendVerificationOrLog(account)
+	return nil
+}
+
+func (s *AccountService) SetBlocked(name string, blocked bool) error {
+	account, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	return s.logChange(account, "blocked", s.DB.SetBlocked(account.AccountID, blocked))
+}
+
+func (s *AccountService) SetPermission(name string, permission uint32) error {
+	account, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	return s.logChange(account, "permission", s.DB.SetPermission(account.AccountID, permission))
+}
+
+// SetSecurityCode sets the secondary password checked by game servers, it is stored like passwords.
+func (s *AccountService) SetSecurityCode(name, securityCode string) error {
+	account, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	hash, err := utils.HashPassword(fmt.Sprintf("%s%s", s.Config.Database.DefaultSalt, securityCode))
+	if err != nil {
+		return err
+	}
+	return s.logChange(account, "security code", s.DB.SetSecurityCode(account.AccountID, hash))
+}
+
+// SetFatigueLimit always (true) or never (false) limits the play time of the account, nil applies the configuration.
+func (s *AccountService) SetFatigueLimit(name string, limit *bool) error {
+	account, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	return s.logChange(account, "fatigue limit", s.DB.SetFatigueLimit(account.AccountID, limit))
+}
+
+func (s *AccountService) Delete(name string) error {
+	account, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	return s.logChange(account, "deleted", s.DB.DeleteAccount(account))
+}
+
+func (s *AccountService) checkEmail(email string, accountID uint32) error {
+	if email == "" {
+		return nil
+	}
+	if address, err := netmail.ParseAddress(email); err != nil || address.Address != email || len(email) > emailMaxLength {
+		return ErrInvalidEmail
+	}
+	if s.DB.EmailExists(email, accountID) {
+		return ErrEmailTaken
+	}
+	return nil
+}
+
+func (s *AccountService) hashPassword(password string) (string, error) {
+	salted := fmt.Sprintf("%s%s", s.Config.Database.DefaultSalt, password)
+	if len(password) < PasswordMinLength || len(salted) > passwordMaxLength {
+		return "", ErrInvalidPassword
+	}
+	return utils.HashPassword(salted)
+}
+
+func (s *AccountService) logChange(account *model.Accounts, change string, err error) error {
+	if err != nil {
+		return err
+	}
+	s.Log.Info("Account changed",
+		"function", "AccountService::logChange",
+		"accountID", account.AccountID,
+		"accountName", account.AccountName,
+		"change", change)
+	return nil
+}
@@ -0,0 +1,63 @@
+package entities_test
+
+import (
+	"mononoke-go/entities"
+	"mononoke-go/model"
+	"testing"
+	"time"
+)
+
+func TestDeleteAccountRemovesRelatedData(t *testing.T) {
+	db := newTestDB(t)
+	accounts := &entities.AccountService{DB: db, Log: newTestLogger()}
+	account := &model.Accounts{AccountName: "alice", Email: "alice@example.com"}
+	if err := db.DB.Create(account).Error; err != nil {
+		t.Fatal(err.Error())
+	}
+
+	now := time.Now()
+	related := []interface{}{
+		&model.RecoveryCodes{AccountID: account.AccountID, CodeHash: "code"},
+		&model.EulaAcceptances{AccountID: account.AccountID},
+		&model.AccountBans{AccountID: account.AccountID},
+		&model.AccountTokens{AccountID: account.AccountID, Purpose: model.TokenPurposeLauncher, TokenHash: "hash",
+			ExpiresAt: now.Add(time.Hour)},
+		&model.PremiumEntitlements{AccountID: account.AccountID, StartsAt: now},
+		&model.EventCodes{AccountID: account.AccountID, Code: 1, StartsAt: now},
+		&model.MacStamps{AccountID: account.AccountID, MacStamp: "0011223344556677"},
+		&model.LoginLockouts{Kind: model.LockoutKindAccount, Subject: "alice", LockCount: 3},
+	}
+	for _, row := range related {
+		if err := db.DB.Create(row).Error; err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	campaign := &model.EventCodes{Code: 2, StartsAt: now}
+	if err := db.DB.Create(campaign).Error; err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := accounts.Delete("alice"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, found := db.GetUserByName("alice"); found {
+		t.Error("account still exists")
+	}
+	for _, row := range related {
+		query := db.DB.Model(row).Where("account_id = ?", account.AccountID)
+		if _, isLockout := row.(*model.LoginLockouts); isLockout {
+			query = db.DB.Model(row).Where("subject = ?", "alice")
+		}
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			t.Fatal(err.Error())
+		}
+		if count != 0 {
+			t.Errorf("%T: %d rows left after deleting the account", row, count)
+		}
+	}
+	var campaigns int64
+	if db.DB.Model(campaign).Count(&campaigns); campaigns != 1 {
+		t.Errorf("%d campaign event codes left, want 1", campaigns)
+	}
+}
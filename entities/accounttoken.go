@@ -0,0 +1,132 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"mononoke-go/model"
+	"mononoke-go/utils"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken  = errors.New("invalid or expired token")
+	ErrEmailRequired = errors.New("account has no email address")
+)
+
+// SendVerification sends a new email verification token to the account.
+func (s *AccountService) SendVerification(name string) error {
+	account, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	return s.sendVerification(account)
+}
+
+// VerifyEmail uses a verification token and unlocks the account.
+func (s *AccountService) VerifyEmail(token string) error {
+	accountToken, valid := s.DB.UseAccountToken(model.TokenPurposeVerifyEmail, utils.HashToken(token), time.Now())
+	if !valid {
+		return ErrInvalidToken
+	}
+	if err := s.DB.SetEmailVerificationPending(accountToken.AccountID, false); err != nil {
+		return err
+	}
+	s.Log.Info("Email verified",
+		"function", "AccountService::VerifyEmail",
+		"accountID", accountToken.AccountID)
+	return nil
+}
+
+// RequestPasswordReset sends a password reset token to the email address of the account.
+func (s *AccountService) RequestPasswordReset(name string) error {
+	account, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	if account.Email == "" {
+		return ErrEmailRequired
+	}
+	validity := time.Duration(s.Config.Account.ResetTokenMinutes) * time.Minute
+	token, err := utils.GenerateToken()
+	if err != nil {
+		return err
+	}
+	if err = s.issueToken(account, model.TokenPurposeResetPassword, token, validity); err != nil {
+		return err
+	}
+	body := fmt.Sprintf("Hello %s,\n\nuse the following link or token within %d minutes to reset your password:\n\n%s\n\n"+
+		"If you didn't request a password reset, you can ignore this mail.",
+		account.AccountName, s.Config.Account.ResetTokenMinutes, tokenLink(s.Config.Account.ResetURL, token))
+	return s.Mail.Send(account.Email, "Password reset", body)
+}
+
+// ResetPassword uses a password reset token to set a new password.
+func (s *AccountService) ResetPassword(token, password string) error {
+	hash, err := s.hashPassword(password)
+	if err != nil {
+		return err
+	}
+	accountToken, valid := s.DB.UseAccountToken(model.TokenPurposeResetPassword, utils.HashToken(token), time.Now())
+	if !valid {
+		return ErrInvalidToken
+	}
+	if err = s.DB.UpdatePassword(accountToken.AccountID, hash); err != nil {
+		return err
+	}
+	s.Log.Info("Password reset",
+		"function", "AccountService::ResetPassword",
+		"accountID", accountToken.AccountID)
+	return nil
+}
+
+func (s *AccountService) sendVerification(account *model.Accounts) error {
+	if account.Email == "" {
+		return ErrEmailRequired
+	}
+	validity := time.Duration(s.Config.Account.VerifyTokenHours) * time.Hour
+	token, err := utils.GenerateToken()
+	if err != nil {
+		return err
+	}
+	if err = s.issueToken(account, model.TokenPurposeVerifyEmail, token, validity); err != nil {
+		return err
+	}
+	body := fmt.Sprintf("Hello %s,\n\nuse the following link or token within %d hours to verify your email address:\n\n%s",
+		account.AccountName, s.Config.Account.VerifyTokenHours, tokenLink(s.Config.Account.VerifyURL, token))
+	return s.Mail.Send(account.Email, "Verify your email address", body)
+}
+
+// issueToken stores the token for the account, only the hash of the token is stored.
+func (s *AccountService) issueToken(account *model.Accounts, purpose, token string, validity time.Duration) error {
+	now := time.Now()
+	if err := s.DB.DeleteExpiredAccountTokens(now); err != nil {
+		s.Log.Error("Cannot remove expired tokens",
+			"function", "AccountService::issueToken",
+			"error", err.Error())
+	}
+	return s.DB.CreateAccountToken(&model.AccountTokens{
+		AccountID: account.AccountID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: now.Add(validity),
+	})
+}
+
+// tokenLink inserts the token into the configured URL, without URL the token is sent as is.
+func tokenLink(url, token string) string {
+	if url == "" {
+		return token
+	}
+	return strings.ReplaceAll(url, "{token}", token)
+}
+
+// sendVerificationOrLog doesn't fail the account change if the mail can't be sent, it can be sent again later.
+func (s *AccountService) sendVerificationOrLog(account *model.Accounts) {
+	if err := s.sendVerification(account); err != nil {
+		s.Log.Error("Cannot send verification mail",
+			"function", "AccountService::sendVerificationOrLog",
+			"accountName", account.AccountName,
+			"error", err.Error())
+	}
+}
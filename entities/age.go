@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"mononoke-go/model"
+	"mononoke-go/utils"
+	"time"
+)
+
+// accountAge calculates the age from the birthdate, accounts without birthdate use the stored age.
+func accountAge(account *model.Accounts, now time.Time) uint8 {
+	if account.Birthdate == nil {
+		return account.Age
+	}
+	return uint8(min(utils.AgeAt(*account.Birthdate, now), 255)) //nolint:gosec // age is limited to 255
+}
+
+// minimumAge returns the age required to join the server. Servers without own setting require
+// the AgeRestriction if they are adult servers.
+func (a *AuthHandler) minimumAge(srv *Game) uint8 {
+	if server, found := a.Config.GetGameServer(srv.ServerIdx); found && server.MinAge > 0 {
+		return server.MinAge
+	}
+	if srv.IsAdultServer == 1 {
+		return a.Config.Server.AgeRestriction
+	}
+	return 0
+}
@@ -0,0 +1,144 @@
+package entities
+
+import (
+	"log/slog"
+	"mononoke-go/config"
+	"mononoke-go/database"
+	"mononoke-go/model"
+	"sync"
+	"time"
+)
+
+const auditCleanupInterval = time.Hour
+
+// AuditLog writes login audit entries asynchronously in batches, a nil AuditLog discards all entries.
+type AuditLog struct {
+	DB            *database.GormDatabase
+	Log           *slog.Logger
+	BatchSize     int
+	FlushInterval time.Duration
+	Retention     time.Duration
+	entries       chan model.LoginAudits
+	done          chan struct{}
+	closed        bool
+	mutex         sync.RWMutex
+}
+
+// NewAuditLog creates the audit log from the configuration, returns nil if auditing is disabled.
+func NewAuditLog(db *database.GormDatabase, conf *config.Configuration, log *slog.Logger) *AuditLog {
+	if !conf.Audit.Enabled {
+		return nil
+	}
+	return &AuditLog{
+		DB:            db,
+		Log:           log,
+		BatchSize:     max(int(conf.Audit.BatchSize), 1),
+		FlushInterval: time.Duration(max(conf.Audit.FlushSeconds, 1)) * time.Second,
+		Retention:     time.Duration(conf.Audit.RetentionDays) * 24 * time.Hour,
+		entries:       make(chan model.LoginAudits, conf.Audit.QueueSize),
+		done:          make(chan struct{}),
+	}
+}
+
+// Record queues an entry without blocking, entries are dropped if the queue is full.
+func (al *AuditLog) Record(entry model.LoginAudits) {
+	if al == nil {
+		return
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	al.mutex.RLock()
+	defer al.mutex.RUnlock()
+	if al.closed {
+		return
+	}
+	select {
+	case al.entries <- entry:
+	default:
+		al.Log.Warn("Audit queue full, dropping entry",
+			"function", "AuditLog::Record",
+			"event", entry.Event,
+			"accountName", entry.AccountName)
+	}
+}
+
+// Run writes queued entries until Close is called.
+func (al *AuditLog) Run() {
+	if al == nil {
+		return
+	}
+	flushTicker := time.NewTicker(al.FlushInterval)
+	defer flushTicker.Stop()
+	cleanupTicker := time.NewTicker(auditCleanupInterval)
+	defer cleanupTicker.Stop()
+	al.cleanup()
+
+	batch := make([]model.LoginAudits, 0, al.BatchSize)
+	for {
+		select {
+		case entry, ok := <-al.entries:
+			if !ok {
+				al.flush(batch)
+				close(al.done)
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= al.BatchSize {
+				batch = al.flush(batch)
+			}
+		case <-flushTicker.C:
+			batch = al.flush(batch)
+		case <-cleanupTicker.C:
+			al.cleanup()
+		}
+	}
+}
+
+// Close stops accepting entries and waits until the queued ones are written.
+func (al *AuditLog) Close() {
+	if al == nil {
+		return
+	}
+	al.mutex.Lock()
+	if al.closed {
+		al.mutex.Unlock()
+		return
+	}
+	al.closed = true
+	close(al.entries)
+	al.mutex.Unlock()
+	<-al.done
+}
+
+func (al *AuditLog) flush(batch []model.LoginAudits) []model.LoginAudits {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := al.DB.InsertLoginAudits(batch); err != nil {
+		al.Log.Error("Cannot write audit entries",
+			"function", "AuditLog::flush",
+			"entries", len(batch),
+			"error", err.Error())
+	}
+	return batch[:0]
+}
+
+func (al *AuditLog) cleanup() {
+	if al.Retention <= 0 {
+		return
+	}
+	deleted, err := al.DB.DeleteLoginAuditsBefore(time.Now().Add(-al.Retention))
+	if err != nil {
+		al.Log.Error("Cannot remove old audit entries",
+			"function", "AuditLog::cleanup",
+			"error", err.Error())
+		return
+	}
+	if deleted > 0 {
+		al.Log.Debug("Removed old audit entries",
+			"function", "AuditLog::cleanup",
+			"entries", deleted)
+	}
+}
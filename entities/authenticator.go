@@ -0,0 +1,231 @@
+package entities
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mononoke-go/config"
+	"mononoke-go/database"
+	"mononoke-go/model"
+	"mononoke-go/utils"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	AuthBackendSQL  = "sql"
+	AuthBackendFile = "file"
+	AuthBackendHTTP = "http"
+)
+
+var (
+	ErrUnknownAuthBackend = errors.New("unknown authentication backend")
+	ErrUnexpectedStatus   = errors.New("unexpected status code")
+	ErrMissingAccountID   = errors.New("response without accountId")
+	ErrInvalidAuthURL     = errors.New("invalid authentication URL")
+)
+
+// Authenticator verifies the credentials of an account. Implementations which don't use the local
+// accounts table keep a copy of the account in it, bans, TOTP and EULA acceptances are stored locally.
+type Authenticator interface {
+	Authenticate(accountName, password string) (*model.Accounts, bool)
+}
+
+// NewAuthenticator creates the authenticator selected in the configuration.
+func NewAuthenticator(db *database.GormDatabase, conf *config.Configuration, log *slog.Logger) (Authenticator, error) {
+	switch conf.Auth.Backend {
+	case AuthBackendSQL:
+		return &SQLAuthenticator{DB: db, Config: conf}, nil
+	case AuthBackendFile:
+		return NewFileAuthenticator(db, conf.Auth.File, log)
+	case AuthBackendHTTP:
+		return NewHTTPAuthenticator(db, conf.Auth.HTTP.URL, conf.Auth.HTTP.Token,
+			time.Duration(conf.Auth.HTTP.TimeoutSeconds)*time.Second, log)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAuthBackend, conf.Auth.Backend)
+	}
+}
+
+// SQLAuthenticator verifies the bcrypt password stored in the accounts table.
+type SQLAuthenticator struct {
+	DB     *database.GormDatabase
+	Config *config.Configuration
+}
+
+func (s *SQLAuthenticator) Authenticate(accountName, password string) (*model.Accounts, bool) {
+	return s.DB.GetUserByNameAndPW(accountName,
+		fmt.Sprintf("%s%s", s.Config.Database.DefaultSalt, password), s.Config)
+}
+
+// externalAccount is the account description of the file and the HTTP authenticator.
+type externalAccount struct {
+	AccountID   uint32 `json:"accountId"`
+	AccountName string `json:"accountName"`
+	Password    string `json:"password,omitempty"`
+	Email       string `json:"email"`
+	Age         uint8  `json:"age"`
+	Permission  uint32 `json:"permission"`
+	Blocked     bool   `json:"blocked"`
+}
+
+func (e *externalAccount) sync(db *database.GormDatabase) (*model.Accounts, error) {
+	return db.SyncAccount(&model.Accounts{
+		AccountID:   e.AccountID,
+		AccountName: e.AccountName,
+		Email:       e.Email,
+		Age:         e.Age,
+		Permission:  e.Permission,
+		Blocked:     e.Blocked,
+	})
+}
+
+// FileAuthenticator reads the accounts from a JSON file, meant for tests. Passwords are either
+// bcrypt hashes or plain text.
+type FileAuthenticator struct {
+	DB       *database.GormDatabase
+	Log      *slog.Logger
+	accounts map[string]externalAccount
+}
+
+func NewFileAuthenticator(db *database.GormDatabase, file string, log *slog.Logger) (*FileAuthenticator, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var accounts []externalAccount
+	if err = json.Unmarshal(data, &accounts); err != nil {
+		return nil, fmt.Errorf("invalid account file %s: %w", file, err)
+	}
+
+	authenticator := &FileAuthenticator{DB: db, Log: log, accounts: make(map[string]externalAccount, len(accounts))}
+	for _, account := range accounts {
+		authenticator.accounts[account.AccountName] = account
+	}
+	return authenticator, nil
+}
+
+func (f *FileAuthenticator) Authenticate(accountName, password string) (*model.Accounts, bool) {
+	entry, exists := f.accounts[accountName]
+	if !exists {
+		return nil, false
+	}
+	if strings.HasPrefix(entry.Password, "$2") {
+		if !utils.VerifyPassword(password, entry.Password) {
+			return nil, false
+		}
+	} else if subtle.ConstantTimeCompare([]byte(password), []byte(entry.Password)) != 1 {
+		return nil, false
+	}
+
+	account, err := entry.sync(f.DB)
+	if err != nil {
+		f.Log.Error("Cannot store account",
+			"function", "FileAuthenticator::Authenticate",
+			"accountName", accountName,
+			"error", err.Error())
+		return nil, false
+	}
+	return account, true
+}
+
+// HTTPAuthenticator posts the credentials to a web endpoint. The endpoint answers with status 200 and
+// the account as JSON for valid credentials, with 401, 403 or 404 otherwise.
+type HTTPAuthenticator struct {
+	DB     *database.GormDatabase
+	URL    string
+	Token  string
+	Client *http.Client
+	Log    *slog.Logger
+}
+
+// NewHTTPAuthenticator checks the endpoint URL, so a typo fails at startup instead of every login.
+func NewHTTPAuthenticator(db *database.GormDatabase, endpoint, token string, timeout time.Duration,
+	log *slog.Logger,
+) (*HTTPAuthenticator, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAuthURL, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAuthURL, endpoint)
+	}
+	return &HTTPAuthenticator{
+		DB:     db,
+		URL:    endpoint,
+		Token:  token,
+		Client: &http.Client{Timeout: timeout},
+		Log:    log,
+	}, nil
+}
+
+func (h *HTTPAuthenticator) Authenticate(accountName, password string) (*model.Accounts, bool) {
+	entry, valid, err := h.request(accountName, password)
+	if err != nil {
+		h.Log.Error("Authentication request failed",
+			"function", "HTTPAuthenticator::Authenticate",
+			"accountName", accountName,
+			"error", err.Error())
+		return nil, false
+	}
+	if !valid {
+		return nil, false
+	}
+
+	account, err := entry.sync(h.DB)
+	if err != nil {
+		h.Log.Error("Cannot store account",
+			"function", "HTTPAuthenticator::Authenticate",
+			"accountName", accountName,
+			"error", err.Error())
+		return nil, false
+	}
+	return account, true
+}
+
+func (h *HTTPAuthenticator) request(accountName, password string) (*externalAccount, bool, error) {
+	body, err := json.Marshal(map[string]string{"accountName": accountName, "password": password})
+	if err != nil {
+		return nil, false, err
+	}
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if h.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+h.Token)
+	}
+
+	response, err := h.Client.Do(request)
+	if err != nil {
+		return nil, false, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("%w: %d", ErrUnexpectedStatus, response.StatusCode)
+	}
+
+	entry := new(externalAccount)
+	if err = json.NewDecoder(response.Body).Decode(entry); err != nil {
+		return nil, false, err
+	}
+	if entry.AccountID == 0 {
+		return nil, false, ErrMissingAccountID
+	}
+	if entry.AccountName == "" {
+		entry.AccountName = accountName
+	}
+	return entry, true, nil
+}
@@ -0,0 +1,177 @@
+package entities_test
+
+import (
+	"encoding/json"
+	"mononoke-go/database"
+	"mononoke-go/entities"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newFileAuthenticator(t *testing.T, db *database.GormDatabase, accounts string) *entities.FileAuthenticator {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "accounts.json")
+	if err := os.WriteFile(file, []byte(accounts), 0o600); err != nil {
+		t.Fatal(err.Error())
+	}
+	authenticator, err := entities.NewFileAuthenticator(db, file, newTestLogger())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return authenticator
+}
+
+func TestFileAuthenticator(t *testing.T) {
+	db := newTestDB(t)
+	authenticator := newFileAuthenticator(t, db, `[
+		{"accountId": 10, "accountName": "alice", "password": "secret", "age": 20, "permission": 1},
+		{"accountId": 11, "accountName": "bob", "password": "$2a$14$YYtz2pCu3YBI8fOVYUSYuOXAgkBzeOZO2k02p/JqUpUFzBYJ8AE9O"}
+	]`)
+
+	account, valid := authenticator.Authenticate("alice", "secret")
+	if !valid || account.AccountID != 10 || account.Permission != 1 {
+		t.Fatalf("Authenticate(alice) = %v, %t, want account 10", account, valid)
+	}
+	if stored, found := db.GetUserByID(10); !found || stored.AccountName != "alice" || stored.Age != 20 {
+		t.Errorf("account 10 not stored, got %v", stored)
+	}
+
+	if _, valid = authenticator.Authenticate("bob", "helloworld"); !valid {
+		t.Error("Authenticate(bob) with bcrypt password failed")
+	}
+	if _, valid = authenticator.Authenticate("alice", "wrong"); valid {
+		t.Error("Authenticate(alice) accepted a wrong password")
+	}
+	if _, valid = authenticator.Authenticate("carol", "secret"); valid {
+		t.Error("Authenticate(carol) accepted an unknown account")
+	}
+}
+
+func TestFileAuthenticatorUpdatesAccount(t *testing.T) {
+	db := newTestDB(t)
+	first := newFileAuthenticator(t, db, `[{"accountId": 10, "accountName": "alice", "password": "secret"}]`)
+	if _, valid := first.Authenticate("alice", "secret"); !valid {
+		t.Fatal("Authenticate(alice) failed")
+	}
+
+	second := newFileAuthenticator(t, db,
+		`[{"accountId": 10, "accountName": "alice", "password": "secret", "permission": 100, "blocked": true}]`)
+	account, valid := second.Authenticate("alice", "secret")
+	if !valid || account.Permission != 100 || !account.Blocked {
+		t.Errorf("Authenticate(alice) = %v, %t, want updated permission and blocked", account, valid)
+	}
+}
+
+func TestFileAuthenticatorRefusesConflicts(t *testing.T) {
+	db := newTestDB(t)
+	authenticator := newFileAuthenticator(t, db, `[
+		{"accountId": 1, "accountName": "mallory", "password": "secret", "permission": 100},
+		{"accountId": 20, "accountName": "TEST", "password": "secret"}
+	]`)
+
+	if _, valid := authenticator.Authenticate("mallory", "secret"); valid {
+		t.Error("Authenticate(mallory) took over the ID of the default account")
+	}
+	if account, found := db.GetUserByID(1); !found || account.AccountName != "test" || account.Permission != 0 {
+		t.Errorf("default account changed to %v", account)
+	}
+
+	if _, valid := authenticator.Authenticate("TEST", "secret"); valid {
+		t.Error("Authenticate(TEST) created a second account with the name of the default account")
+	}
+	if _, found := db.GetUserByID(20); found {
+		t.Error("account 20 was created")
+	}
+}
+
+func newHTTPAuthenticator(t *testing.T, db *database.GormDatabase, handler http.HandlerFunc,
+	timeout time.Duration,
+) *entities.HTTPAuthenticator {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	authenticator, err := entities.NewHTTPAuthenticator(db, server.URL, "secret-token", timeout, newTestLogger())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return authenticator
+}
+
+func TestHTTPAuthenticator(t *testing.T) {
+	db := newTestDB(t)
+	authenticator := newHTTPAuthenticator(t, db, func(w http.ResponseWriter, r *http.Request) {
+		var credentials struct {
+			AccountName string `json:"accountName"`
+			Password    string `json:"password"`
+		}
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if credentials.AccountName != "alice" || credentials.Password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"accountId": 10, "email": "alice@example.com", "permission": 1}`))
+	}, time.Second)
+
+	account, valid := authenticator.Authenticate("alice", "secret")
+	if !valid || account.AccountID != 10 || account.AccountName != "alice" || account.Permission != 1 {
+		t.Fatalf("Authenticate(alice) = %v, %t, want account 10", account, valid)
+	}
+	if stored, found := db.GetUserByID(10); !found || stored.Email != "alice@example.com" {
+		t.Errorf("account 10 not stored, got %v", stored)
+	}
+	if _, valid = authenticator.Authenticate("alice", "wrong"); valid {
+		t.Error("Authenticate(alice) accepted a wrong password")
+	}
+}
+
+func TestHTTPAuthenticatorFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"non-200 status", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}},
+		{"missing account ID", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"accountName": "alice"}`))
+		}},
+		{"timeout", func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			_, _ = w.Write([]byte(`{"accountId": 10}`))
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newTestDB(t)
+			authenticator := newHTTPAuthenticator(t, db, test.handler, 50*time.Millisecond)
+			if account, valid := authenticator.Authenticate("alice", "secret"); valid {
+				t.Errorf("Authenticate(alice) = %v, want rejection", account)
+			}
+			if _, found := db.GetUserByID(10); found {
+				t.Error("account 10 stored after a failed request")
+			}
+		})
+	}
+}
+
+func TestNewHTTPAuthenticatorInvalidURL(t *testing.T) {
+	for _, endpoint := range []string{"", "localhost:8080/auth", "http://", "ftp://example.com/auth", "http://[::1"} {
+		if _, err := entities.NewHTTPAuthenticator(nil, endpoint, "", time.Second, newTestLogger()); err == nil {
+			t.Errorf("NewHTTPAuthenticator(%q) succeeded, want error", endpoint)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package entities
+
+import (
+	"bufio"
+	"log/slog"
+	"mononoke-go/database"
+	"mononoke-go/utils"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type bannedNetwork struct {
+	Prefix    netip.Prefix
+	Reason    string
+	ExpiresAt *time.Time
+}
+
+// BanList holds the banned IPs and CIDR ranges from the database and the optional ban file.
+type BanList struct {
+	DB       *database.GormDatabase
+	File     string
+	Log      *slog.Logger
+	networks []bannedNetwork
+	mutex    sync.RWMutex
+}
+
+// Reload replaces the current ban list with the entries from database and file.
+func (bl *BanList) Reload() error {
+	bans, err := bl.DB.GetActiveIPBans()
+	if err != nil {
+		return err
+	}
+
+	networks := make([]bannedNetwork, 0, len(bans))
+	for _, ban := range bans {
+		prefix, parseErr := utils.ParseIPOrCIDR(ban.Network)
+		if parseErr != nil {
+			bl.Log.Warn("Invalid IP ban in database",
+				"function", "BanList::Reload",
+				"id", ban.ID,
+				"network", ban.Network,
+				"error", parseErr.Error())
+			continue
+		}
+		networks = append(networks, bannedNetwork{Prefix: prefix, Reason: ban.Reason, ExpiresAt: ban.ExpiresAt})
+	}
+
+	if bl.File != "" {
+		fileNetworks, fileErr := bl.readFile()
+		if fileErr != nil {
+			return fileErr
+		}
+		networks = append(networks, fileNetworks...)
+	}
+
+	bl.mutex.Lock()
+	bl.networks = networks
+	bl.mutex.Unlock()
+	bl.Log.Debug("Ban list reloaded",
+		"function", "BanList::Reload",
+		"entries", len(networks))
+	return nil
+}
+
+// IsBanned checks if the IP is part of a banned network and returns the ban reason.
+func (bl *BanList) IsBanned(ip string) (string, bool) {
+	now := time.Now()
+	bl.mutex.RLock()
+	defer bl.mutex.RUnlock()
+	for _, network := range bl.networks {
+		if network.ExpiresAt != nil && network.ExpiresAt.Before(now) {
+			continue
+		}
+		if utils.PrefixContainsIP(network.Prefix, ip) {
+			return network.Reason, true
+		}
+	}
+	return "", false
+}
+
+// readFile parses one IP or CIDR range per line, optionally followed by a reason. Lines starting with # are ignored.
+func (bl *BanList) readFile() ([]bannedNetwork, error) {
+	file, err := os.Open(bl.File)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var networks []bannedNetwork
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		prefix, parseErr := utils.ParseIPOrCIDR(fields[0])
+		if parseErr != nil {
+			bl.Log.Warn("Invalid IP ban in file",
+				"function", "BanList::readFile",
+				"file", bl.File,
+				"line", lineNumber,
+				"error", parseErr.Error())
+			continue
+		}
+		networks = append(networks, bannedNetwork{Prefix: prefix, Reason: strings.Join(fields[1:], " ")})
+	}
+	return networks, scanner.Err()
+}
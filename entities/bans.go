@@ -0,0 +1,148 @@
+package entities
+
+import (
+	"errors"
+	"log/slog"
+	"mononoke-go/database"
+	"mononoke-go/model"
+	"mononoke-go/utils"
+	"time"
+)
+
+var (
+	ErrInvalidNetwork = errors.New("invalid IP or CIDR range")
+	ErrIPBanNotFound  = errors.New("IP or CIDR range is not banned")
+	ErrNotBanned      = errors.New("account has no active ban")
+)
+
+// BanService manages IP and account bans for the admin API and the CLI.
+type BanService struct {
+	DB       *database.GormDatabase
+	Accounts *AccountService
+	// Bans is reloaded after every change, it is nil for the CLI.
+	Bans *BanList
+	Log  *slog.Logger
+}
+
+func (s *BanService) BanIP(network, reason, createdBy string, expiresAt *time.Time) error {
+	normalized, err := normalizeNetwork(network)
+	if err != nil {
+		return err
+	}
+	err = s.DB.SaveIPBan(&model.IPBans{
+		Network:   normalized,
+		Reason:    reason,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+	s.Log.Info("IP banned",
+		"function", "BanService::BanIP",
+		"network", normalized,
+		"reason", reason,
+		"expiresAt", expiresAt)
+	s.reload()
+	return nil
+}
+
+func (s *BanService) UnbanIP(network string) error {
+	normalized, err := normalizeNetwork(network)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.DB.DeleteIPBan(normalized)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrIPBanNotFound
+	}
+	s.Log.Info("IP unbanned",
+		"function", "BanService::UnbanIP",
+		"network", normalized)
+	s.reload()
+	return nil
+}
+
+// IPBans returns the active IP bans stored in the database, the ban file is not included.
+func (s *BanService) IPBans() ([]model.IPBans, error) {
+	return s.DB.GetActiveIPBans()
+}
+
+func (s *BanService) reload() {
+	if s.Bans == nil {
+		return
+	}
+	if err := s.Bans.Reload(); err != nil {
+		s.Log.Error("Cannot reload ban list",
+			"function", "BanService::reload",
+			"error", err.Error())
+	}
+}
+
+// BanAccount bans the account for the duration, 0 bans it permanently.
+func (s *BanService) BanAccount(
+	name string, duration time.Duration, reason, issuer string,
+) (*model.AccountBans, error) {
+	account, err := s.Accounts.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	ban := &model.AccountBans{AccountID: account.AccountID, StartsAt: now, Reason: reason, Issuer: issuer}
+	if duration > 0 {
+		endsAt := now.Add(duration)
+		ban.EndsAt = &endsAt
+	}
+	if err = s.DB.AddAccountBan(ban); err != nil {
+		return nil, err
+	}
+	s.Log.Info("Account banned",
+		"function", "BanService::BanAccount",
+		"accountName", account.AccountName,
+		"endsAt", ban.EndsAt,
+		"reason", reason)
+	return ban, nil
+}
+
+func (s *BanService) LiftAccountBan(name string) error {
+	account, err := s.Accounts.Get(name)
+	if err != nil {
+		return err
+	}
+	lifted, err := s.DB.LiftAccountBans(account.AccountID)
+	if err != nil {
+		return err
+	}
+	if lifted == 0 {
+		return ErrNotBanned
+	}
+	s.Log.Info("Account ban lifted",
+		"function", "BanService::LiftAccountBan",
+		"accountName", account.AccountName)
+	return nil
+}
+
+// AccountBans returns the ban history of the account.
+func (s *BanService) AccountBans(name string) ([]model.AccountBans, error) {
+	account, err := s.Accounts.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.DB.GetAccountBans(account.AccountID)
+}
+
+// normalizeNetwork validates an IP or CIDR range, single IPs are stored without prefix length.
+func normalizeNetwork(network string) (string, error) {
+	prefix, err := utils.ParseIPOrCIDR(network)
+	if err != nil {
+		return "", ErrInvalidNetwork
+	}
+	if prefix.IsSingleIP() {
+		return prefix.Addr().String(), nil
+	}
+	return prefix.String(), nil
+}
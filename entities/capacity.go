@@ -0,0 +1,73 @@
+package entities
+
+// serverCapacity returns the player limit of a server, 0 for unlimited.
+func (a *AuthHandler) serverCapacity(serverIdx uint32) uint32 {
+	if server, found := a.Config.GetGameServer(serverIdx); found && server.Capacity > 0 {
+		return server.Capacity
+	}
+	return a.Config.Queue.DefaultCapacity
+}
+
+// userRatio returns the load of a server in percent, reported loads are preferred over own counts.
+func (a *AuthHandler) userRatio(srv *Game) uint16 {
+	users := a.Players.CountConfirmed(srv.ServerIdx)
+	capacity := a.serverCapacity(srv.ServerIdx)
+	if srv.HasLoadReport {
+		users = srv.ReportedUsers
+		if srv.ReportedCapacity > 0 {
+			capacity = srv.ReportedCapacity
+		}
+	}
+	if capacity == 0 {
+		return 0
+	}
+	return uint16(min(users*100/capacity, 100)) //nolint:gosec // ratio is limited to 100
+}
+
+// admitToServer checks if the player may join the server now, otherwise the player is queued.
+// Returns the estimated waiting time in seconds for queued players.
+func (a *AuthHandler) admitToServer(player *Player, serverIdx uint32) (uint32, bool) {
+	capacity := a.serverCapacity(serverIdx)
+	if capacity == 0 {
+		a.Queue.Remove(player.AccountName)
+		return 0, true
+	}
+
+	free := 0
+	if used := a.Players.CountInGame(serverIdx); used < capacity {
+		free = int(capacity - used)
+	}
+
+	priority := player.Permission >= a.Config.Queue.PriorityPermission
+	position := a.Queue.Enqueue(serverIdx, player.AccountName, priority)
+	if position < free {
+		a.Queue.Remove(player.AccountName)
+		return 0, true
+	}
+	return uint32(position-free+1) * a.Config.Queue.SecondsPerPlayer, false //nolint:gosec // position is positive
+}
+
+// admitQueued sends the one-time key to queued players as long as the server has free slots.
+func (a *AuthHandler) admitQueued(serverIdx uint32) {
+	for {
+		if _, online := a.GameSrvs.GetGame(serverIdx); !online {
+			return
+		}
+		accountName, priority, queued := a.Queue.Next(serverIdx)
+		if !queued {
+			return
+		}
+		player := a.Players.GetPlayer(accountName)
+		if player == nil || player.Client == nil || player.AuthDisconnected || player.IsInGame {
+			continue
+		}
+		if !a.sendOneTimeKey(player.Client, player, serverIdx) {
+			a.Queue.Requeue(serverIdx, accountName, priority)
+			return
+		}
+		a.Log.Info("Slot freed, admitted queued player",
+			"function", "AuthHandler::admitQueued",
+			"serverIdx", serverIdx,
+			"accountName", accountName)
+	}
+}
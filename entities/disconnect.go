@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"mononoke-go/model"
+	"time"
+)
+
+// gameServerLost releases the players of a disconnected game server, after the grace period if one is configured.
+func (a *GameHandler) gameServerLost(serverIdx uint32) {
+	grace := time.Duration(a.Config.Server.AuthGame.ReconnectGraceSeconds) * time.Second
+	if grace == 0 {
+		a.releasePlayers(serverIdx)
+		return
+	}
+
+	time.AfterFunc(grace, func() {
+		if _, reconnected := a.List.GetGame(serverIdx); reconnected {
+			a.Log.Info("Game server reconnected within grace period, keeping its players",
+				"function", "GameHandler::gameServerLost",
+				"serverIdx", serverIdx)
+			return
+		}
+		a.releasePlayers(serverIdx)
+	})
+}
+
+// releasePlayers logs out the players of the given server and releases the reservations of those about to join.
+func (a *GameHandler) releasePlayers(serverIdx uint32) {
+	players, released := a.PlayerList.ReleaseServer(serverIdx)
+	for _, player := range players {
+		a.audit(model.AuditEventLogout, player, serverIdx, 0, "game server disconnected")
+		a.savePlayTime(player, 0)
+	}
+	if len(players) > 0 || released > 0 {
+		a.Log.Warn("Released players of disconnected game server",
+			"function", "GameHandler::releasePlayers",
+			"serverIdx", serverIdx,
+			"players", len(players),
+			"reservations", released)
+	}
+}
+
+// ReleaseServer removes the players the server confirmed to be in game and returns them. Players which are
+// still about to join keep their session, only their reservation is released.
+func (pl *PlayerList) ReleaseServer(serverIdx uint32) ([]*Player, int) {
+	pl.mutex.Lock()
+	defer pl.mutex.Unlock()
+	var confirmed []*Player
+	released := 0
+	for _, player := range pl.Players {
+		switch {
+		case !player.IsInGame || player.GameIndex != serverIdx:
+		case player.IsConfirmedInGame:
+			pl.remove(player)
+			confirmed = append(confirmed, player)
+		default:
+			pl.release(player)
+			released++
+		}
+	}
+	return confirmed, released
+}
@@ -0,0 +1,36 @@
+package entities_test
+
+import (
+	"mononoke-go/config"
+	"testing"
+	"time"
+)
+
+func TestReleaseServer(t *testing.T) {
+	handler := newTestAuthHandler(new(config.Configuration))
+	playing := addTestPlayer(handler, "playing", 0, false)
+	handler.Players.ReserveServer(playing, 1, 0, 1, time.Time{})
+	if valid, reason := handler.Players.ConfirmOneTimeKey(playing, 1, 1, "", false); !valid {
+		t.Fatal(reason)
+	}
+	joining := addTestPlayer(handler, "joining", 0, false)
+	handler.Players.ReserveServer(joining, 1, 0, 2, time.Time{})
+	gone := addTestPlayer(handler, "gone", 0, false)
+	handler.Players.ReserveServer(gone, 1, 0, 3, time.Time{})
+	handler.Players.Disconnect(gone)
+	other := addTestPlayer(handler, "other", 2, true)
+
+	confirmed, released := handler.Players.ReleaseServer(1)
+	if len(confirmed) != 1 || confirmed[0] != playing || released != 2 {
+		t.Errorf("ReleaseServer(1) = %v, %d, want the playing player and 2 reservations", confirmed, released)
+	}
+	if handler.Players.GetPlayer("playing") != nil || handler.Players.GetPlayer("gone") != nil {
+		t.Error("players of the lost server or without auth connection still listed")
+	}
+	if handler.Players.GetPlayer("joining") != joining || joining.IsInGame || joining.OneTimeKey != 0 {
+		t.Error("player waiting in server selection lost the session or kept the reservation")
+	}
+	if handler.Players.GetPlayer("other") != other || !other.IsInGame {
+		t.Error("player of another server released")
+	}
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"time"
+)
+
+const duplicateLoginPolicyReject = "reject"
+
+// resolveDuplicateLogin ends an existing session of the account according to the configured policy.
+// Returns false if the new login has to be rejected.
+func (a *AuthHandler) resolveDuplicateLogin(player *Player) bool {
+	existing := a.Players.GetPlayer(player.AccountName)
+	if existing == nil {
+		return true
+	}
+
+	if a.Config.Server.DuplicateLogin.Policy == duplicateLoginPolicyReject {
+		a.Log.Info("Duplicate login rejected",
+			"function", "AuthHandler::resolveDuplicateLogin",
+			"accountName", player.AccountName,
+			"isInGame", existing.IsInGame)
+		return false
+	}
+
+	if !existing.IsInGame {
+		// The old session is only connected to us, so we can drop it right away.
+		a.Players.RemovePlayer(existing)
+		if existing.Client != nil {
+			existing.Client.Close()
+		}
+		return true
+	}
+
+	srv, found := a.GameSrvs.GetGame(existing.GameIndex)
+	if !found {
+		a.Players.RemovePlayer(existing)
+		return true
+	}
+
+	if a.Players.StartKick(existing) {
+		a.Log.Info("Kicking existing session",
+			"function", "AuthHandler::resolveDuplicateLogin",
+			"accountName", existing.AccountName,
+			"serverIdx", existing.GameIndex)
+		a.Games.KickPlayer(existing.AccountName, srv)
+	}
+
+	timeout := time.Duration(a.Config.Server.DuplicateLogin.KickTimeoutSeconds) * time.Second
+	if !a.Players.WaitForRemoval(existing, timeout) {
+		a.Players.CancelKick(existing)
+		a.Log.Warn("Game server did not answer kick request in time",
+			"function", "AuthHandler::resolveDuplicateLogin",
+			"accountName", existing.AccountName,
+			"serverIdx", existing.GameIndex)
+		return false
+	}
+	if existing.Client != nil {
+		existing.Client.Close()
+	}
+	return true
+}
+
+// StartKick marks the player to be kicked, returns false if a kick is already pending.
+func (pl *PlayerList) StartKick(player *Player) bool {
+	pl.mutex.Lock()
+	defer pl.mutex.Unlock()
+	if player.KickNextLogin {
+		return false
+	}
+	player.KickNextLogin = true
+	return true
+}
+
+// CancelKick lets the next login of the account kick the player again.
+func (pl *PlayerList) CancelKick(player *Player) {
+	pl.mutex.Lock()
+	player.KickNextLogin = false
+	pl.mutex.Unlock()
+}
@@ -0,0 +1,179 @@
+package entities_test
+
+import (
+	"encoding/binary"
+	"io"
+	"mononoke-go/config"
+	"mononoke-go/entities"
+	"mononoke-go/net"
+	"mononoke-go/net/packets/game"
+	gonet "net"
+	"testing"
+	"time"
+)
+
+// newTestClient connects to a local server and returns its client together with the remote end of the connection.
+func newTestClient(t *testing.T) (*net.Client, gonet.Conn) {
+	t.Helper()
+	listener, err := gonet.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	server := net.NewTCPServer(address, false, "", newTestLogger())
+	clients := make(chan *net.Client, 1)
+	server.OnNewClient(func(c *net.Client) { clients <- c })
+	go server.Listen() //nolint:errcheck // the listener lives until the test binary exits
+
+	var conn gonet.Conn
+	for range 50 {
+		if conn, err = gonet.Dial("tcp", address); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	select {
+	case c := <-clients:
+		return c, conn
+	case <-time.After(time.Second):
+		t.Fatal("client not accepted")
+		return nil, nil
+	}
+}
+
+// readPacket reads the next packet sent to conn and returns its ID and the whole packet including the header.
+func readPacket(conn gonet.Conn) (uint16, []byte, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		return 0, nil, err
+	}
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return 0, nil, err
+	}
+	packet := make([]byte, binary.LittleEndian.Uint32(header))
+	copy(packet, header)
+	if _, err := io.ReadFull(conn, packet[7:]); err != nil {
+		return 0, nil, err
+	}
+	return binary.LittleEndian.Uint16(header[4:]), packet, nil
+}
+
+// assertClosed checks that the server closed the connection of a session.
+func assertClosed(t *testing.T, conn gonet.Conn) {
+	t.Helper()
+	if err := conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("connection of the existing session not closed, read returned %v", err)
+	}
+}
+
+func addTestPlayer(handler *entities.AuthHandler, accountName string, serverIdx uint32, inGame bool) *entities.Player {
+	player := &entities.Player{AccountName: accountName, GameIndex: serverIdx, IsInGame: inGame}
+	handler.Players.AddPlayer(player)
+	return player
+}
+
+func TestDuplicateLoginRejectPolicy(t *testing.T) {
+	conf := new(config.Configuration)
+	conf.Server.DuplicateLogin.Policy = "reject"
+	handler := newTestAuthHandler(conf)
+	existing := addTestPlayer(handler, "alice", 0, false)
+
+	if handler.ResolveDuplicateLogin(&entities.Player{AccountName: "alice"}) {
+		t.Error("duplicate login accepted with reject policy")
+	}
+	if handler.Players.GetPlayer("alice") != existing {
+		t.Error("existing session removed with reject policy")
+	}
+}
+
+func TestDuplicateLoginDropsSessionNotInGame(t *testing.T) {
+	handler := newTestAuthHandler(new(config.Configuration))
+	existing := addTestPlayer(handler, "alice", 0, false)
+	var conn gonet.Conn
+	existing.Client, conn = newTestClient(t)
+
+	if !handler.ResolveDuplicateLogin(&entities.Player{AccountName: "alice"}) {
+		t.Fatal("duplicate login rejected")
+	}
+	if handler.Players.GetPlayer("alice") != nil {
+		t.Error("existing session still listed")
+	}
+	assertClosed(t, conn)
+}
+
+func TestDuplicateLoginKicksPlayerInGame(t *testing.T) {
+	conf := new(config.Configuration)
+	conf.Server.DuplicateLogin.KickTimeoutSeconds = 5
+	handler := newTestAuthHandler(conf)
+	gameClient, gameConn := newTestClient(t)
+	handler.GameSrvs.AddGame(&entities.Game{Client: gameClient, ServerIdx: 1})
+	existing := addTestPlayer(handler, "alice", 1, true)
+	var conn gonet.Conn
+	existing.Client, conn = newTestClient(t)
+
+	kicked := make(chan uint16, 1)
+	go func() {
+		packetID, _, _ := readPacket(gameConn)
+		kicked <- packetID
+		// The game server answers the kick with the logout of the player.
+		handler.Players.RemovePlayer(existing)
+	}()
+
+	if !handler.ResolveDuplicateLogin(&entities.Player{AccountName: "alice"}) {
+		t.Fatal("duplicate login rejected although the game server logged the player out")
+	}
+	if packetID := <-kicked; packetID != game.AuthGameKickClientID {
+		t.Errorf("game server received packet %d, want %d", packetID, game.AuthGameKickClientID)
+	}
+	assertClosed(t, conn)
+}
+
+func TestDuplicateLoginKickTimeout(t *testing.T) {
+	conf := new(config.Configuration)
+	conf.Server.DuplicateLogin.KickTimeoutSeconds = 1
+	handler := newTestAuthHandler(conf)
+	gameClient, gameConn := newTestClient(t)
+	handler.GameSrvs.AddGame(&entities.Game{Client: gameClient, ServerIdx: 1})
+	existing := addTestPlayer(handler, "alice", 1, true)
+
+	start := time.Now()
+	if handler.ResolveDuplicateLogin(&entities.Player{AccountName: "alice"}) {
+		t.Fatal("duplicate login accepted although the game server did not answer the kick")
+	}
+	if waited := time.Since(start); waited < time.Second {
+		t.Errorf("login rejected after %s, want the kick timeout of 1s", waited)
+	}
+	if packetID, _, err := readPacket(gameConn); err != nil || packetID != game.AuthGameKickClientID {
+		t.Errorf("game server received packet %d (%v), want %d", packetID, err, game.AuthGameKickClientID)
+	}
+	if handler.Players.GetPlayer("alice") != existing {
+		t.Error("existing session removed without answer of the game server")
+	}
+	if existing.KickNextLogin {
+		t.Error("KickNextLogin still set, the next login would not kick again")
+	}
+}
+
+func TestDuplicateLoginWaitsForPendingKick(t *testing.T) {
+	conf := new(config.Configuration)
+	conf.Server.DuplicateLogin.KickTimeoutSeconds = 5
+	handler := newTestAuthHandler(conf)
+	handler.GameSrvs.AddGame(&entities.Game{ServerIdx: 1})
+	existing := addTestPlayer(handler, "alice", 1, true)
+	existing.KickNextLogin = true
+
+	time.AfterFunc(100*time.Millisecond, func() { handler.Players.RemovePlayer(existing) })
+	if !handler.ResolveDuplicateLogin(&entities.Player{AccountName: "alice"}) {
+		t.Error("login rejected although the pending kick completed")
+	}
+}
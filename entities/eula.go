@@ -0,0 +1,12 @@
+package entities
+
+import "mononoke-go/net/packets/client"
+
+// eulaLoginFlag returns the login flag for the account, false if it didn't accept the current EULA.
+func (a *AuthHandler) eulaLoginFlag(accountID uint32) (int32, bool) {
+	version, exists := a.DB.GetCurrentEulaVersion()
+	if !exists || a.DB.HasAcceptedEula(accountID, version) {
+		return client.LoginFlagEulaAccepted, true
+	}
+	return 0, false
+}
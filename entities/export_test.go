@@ -0,0 +1,33 @@
+package entities
+
+// Exports of unexported handler functions for the tests in entities_test.
+
+func (a *AuthHandler) CheckLockout(accountName, ip string) (uint16, bool) {
+	return a.checkLockout(accountName, ip)
+}
+
+func (a *AuthHandler) RegisterLoginFailure(accountName, ip string) {
+	a.registerLoginFailure(accountName, ip)
+}
+
+func (a *AuthHandler) ResetLoginFailures(accountName string) {
+	a.resetLoginFailures(accountName)
+}
+
+var LockoutDuration = lockoutDuration
+
+func (a *AuthHandler) ResolveDuplicateLogin(player *Player) bool {
+	return a.resolveDuplicateLogin(player)
+}
+
+func (a *AuthHandler) MaintenanceFor(permission, serverIdx uint32, checkServer bool) (string, bool) {
+	return a.maintenanceFor(permission, serverIdx, checkServer)
+}
+
+func (a *AuthHandler) AdmitToServer(player *Player, serverIdx uint32) (uint32, bool) {
+	return a.admitToServer(player, serverIdx)
+}
+
+func (a *AuthHandler) AdmitQueued(serverIdx uint32) {
+	a.admitQueued(serverIdx)
+}
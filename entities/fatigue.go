@@ -0,0 +1,90 @@
+package entities
+
+import (
+	"mononoke-go/config"
+	"mononoke-go/model"
+	"mononoke-go/net/packets"
+	"time"
+)
+
+// continuousPlayTime returns the continuous play time and the time since the last logout in seconds.
+// The play time starts again once the account was logged out for resetMinutes.
+func continuousPlayTime(account *model.Accounts, resetMinutes uint32, now time.Time) (uint32, uint32) {
+	if account.LastLogoutAt == nil {
+		return 0, 0
+	}
+	logoutTime := max(now.Sub(*account.LastLogoutAt), 0)
+	if resetMinutes > 0 && logoutTime >= time.Duration(resetMinutes)*time.Minute {
+		return 0, uint32(logoutTime.Seconds())
+	}
+	return account.ContinuousPlayTime, uint32(logoutTime.Seconds())
+}
+
+// fatigueLimited checks if the play time of the account is limited. An account setting takes precedence,
+// otherwise accounts with the exempt permission are not limited and all others if they are too young.
+func fatigueLimited(conf *config.Configuration, account *model.Accounts, age uint8) bool {
+	if account.FatigueLimit != nil {
+		return *account.FatigueLimit
+	}
+	if conf.Fatigue.ExemptPermission > 0 && account.Permission >= conf.Fatigue.ExemptPermission {
+		return false
+	}
+	return conf.Fatigue.MaxAge == 0 || age < conf.Fatigue.MaxAge
+}
+
+// checkFatigue returns the result code to send if the player reached the play time limit.
+func (a *AuthHandler) checkFatigue(player *Player) (uint16, bool) {
+	conf := a.Config.Fatigue
+	if !conf.Enabled {
+		return packets.ResultSuccess, false
+	}
+
+	account, found := a.DB.GetUserByID(player.AccountID)
+	if !found || !fatigueLimited(a.Config, account, player.Age) {
+		return packets.ResultSuccess, false
+	}
+	playTime, _ := continuousPlayTime(account, conf.ResetMinutes, time.Now())
+	playMinutes := playTime / 60
+
+	if conf.HarmfulMinutes > 0 && playMinutes >= conf.HarmfulMinutes {
+		a.Log.Info("Server selection rejected, play time is harmful",
+			"function", "AuthHandler::checkFatigue",
+			"accountName", player.AccountName,
+			"playMinutes", playMinutes)
+		return packets.ResultGameTimeHarmful, true
+	}
+	if conf.RejectWhenTired && conf.TiredMinutes > 0 && playMinutes >= conf.TiredMinutes {
+		a.Log.Info("Server selection rejected, player is tired",
+			"function", "AuthHandler::checkFatigue",
+			"accountName", player.AccountName,
+			"playMinutes", playMinutes)
+		return packets.ResultGameTimeTired, true
+	}
+	return packets.ResultSuccess, false
+}
+
+// savePlayTime stores the play time of the finished session. Game servers not reporting a continuous
+// play time get it calculated from the session length.
+func (a *GameHandler) savePlayTime(player *Player, reportedPlayTime uint32) {
+	now := time.Now()
+	loginAt := player.GameLoginAt
+	if loginAt.IsZero() {
+		loginAt = now
+	}
+	sessionTime := uint32(now.Sub(loginAt).Seconds())
+
+	playTime := reportedPlayTime
+	if playTime == 0 {
+		if account, found := a.DB.GetUserByID(player.AccountID); found {
+			playTime, _ = continuousPlayTime(account, a.Config.Fatigue.ResetMinutes, loginAt)
+		}
+		playTime += sessionTime
+	}
+
+	if err := a.DB.UpdatePlayTime(player.AccountID, sessionTime, playTime, now); err != nil {
+		a.Log.Error("Cannot save play time",
+			"function", "GameHandler::savePlayTime",
+			"accountName", player.AccountName,
+			"error", err.Error())
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"encoding/binary"
 	"fmt"
 	"log/slog"
+	"mononoke-go/config"
 	"mononoke-go/database"
+	"mononoke-go/model"
 	"mononoke-go/net"
 	"mononoke-go/net/packets"
 	"mononoke-go/net/packets/game"
 	"mononoke-go/utils"
 	"sync"
+	"time"
 )
 
 type Game struct {
@@ -21,6 +24,10 @@ type Game struct {
 	IsAdultServer       byte
 	ServerIP            string
 	ServerPort          int32
+	HasLoadReport       bool
+	ReportedUsers       uint32
+	ReportedCapacity    uint32
+	LastSeen            time.Time
 }
 
 type GameList struct {
@@ -30,16 +37,73 @@ type GameList struct {
 
 func (gl *GameList) AddGame(game *Game) {
 	gl.mutex.Lock()
+	game.LastSeen = time.Now()
 	gl.Games[game.ServerIdx] = game
 	gl.mutex.Unlock()
 }
 
+// RemoveGame removes the game server, unless another one has registered with its ServerIdx in the meantime.
 func (gl *GameList) RemoveGame(game *Game) {
 	gl.mutex.Lock()
-	delete(gl.Games, game.ServerIdx)
+	if gl.Games[game.ServerIdx] == game {
+		delete(gl.Games, game.ServerIdx)
+	}
 	gl.mutex.Unlock()
 }
 
+// Touch marks the game server as alive.
+func (gl *GameList) Touch(key uint32) {
+	gl.mutex.Lock()
+	if game, ok := gl.Games[key]; ok {
+		game.LastSeen = time.Now()
+	}
+	gl.mutex.Unlock()
+}
+
+// StaleGame is a game server which stopped answering, LastSeen is captured while the list is locked.
+type StaleGame struct {
+	Game     *Game
+	LastSeen time.Time
+}
+
+// Stale returns the game servers which were last seen before the given time.
+func (gl *GameList) Stale(before time.Time) []StaleGame {
+	gl.mutex.Lock()
+	defer gl.mutex.Unlock()
+	var stale []StaleGame
+	for _, game := range gl.Games {
+		if game.LastSeen.Before(before) {
+			stale = append(stale, StaleGame{Game: game, LastSeen: game.LastSeen})
+		}
+	}
+	return stale
+}
+
+// All returns the registered game servers.
+func (gl *GameList) All() []*Game {
+	gl.mutex.Lock()
+	defer gl.mutex.Unlock()
+	games := make([]*Game, 0, len(gl.Games))
+	for _, game := range gl.Games {
+		games = append(games, game)
+	}
+	return games
+}
+
+// UpdateLoad stores the player count reported by a game server.
+func (gl *GameList) UpdateLoad(key, users, capacity uint32) bool {
+	gl.mutex.Lock()
+	defer gl.mutex.Unlock()
+	game, ok := gl.Games[key]
+	if !ok {
+		return false
+	}
+	game.HasLoadReport = true
+	game.ReportedUsers = users
+	game.ReportedCapacity = capacity
+	return true
+}
+
 func (gl *GameList) GetGame(key uint32) (*Game, bool) {
 	gl.mutex.Lock()
 	game, ok := gl.Games[key]
@@ -51,7 +115,12 @@ type GameHandler struct {
 	List       *GameList
 	PlayerList *PlayerList
 	DB         *database.GormDatabase
+	Audit      *AuditLog
+	Config     *config.Configuration
 	Log        *slog.Logger
+	pending    sync.Map
+	// slotFreed is called when a player leaves a server, it admits the next queued player.
+	slotFreed func(serverIdx uint32)
 }
 
 func (a *GameHandler) InitServer(server *net.Server) {
@@ -59,8 +128,10 @@ func (a *GameHandler) InitServer(server *net.Server) {
 		go a.HandleMessage(c, header, message)
 	})
 	server.OnClientConnectionClosed(func(c *net.Client, err error) {
-		if game, exists := a.List.GetGame(c.GameIdentifier); exists && game != nil {
+		a.pending.Delete(c)
+		if game, exists := a.List.GetGame(c.GameIdentifier); exists && game != nil && game.Client == c {
 			a.List.RemoveGame(game)
+			a.gameServerLost(game.ServerIdx)
 		}
 		var message string
 		if err != nil {
@@ -95,16 +166,18 @@ func (a *GameHandler) parseMessage(_ *net.Client, msg []byte, packetName string,
 }
 
 func (a *GameHandler) HandleMessage(c *net.Client, header packets.Message, msg []byte) {
+	if c.IsAuthenticated {
+		a.List.Touch(c.GameIdentifier)
+	}
 	switch header.HeaderMessageId {
-	case game.AuthGameKickClientID:
+	case game.GameAuthClientKickFailedID:
 		clientKickFailedPkt := game.GameAuthClientKickFailed{}
 		if err := a.parseMessage(c, msg, "GameAuthClientKickFailed", &clientKickFailedPkt); err == nil {
 			a.HandleClientKickFailed(c, clientKickFailedPkt)
 		}
 	case game.GameAuthClientLoginID:
-		clientLoginPkt := game.GameAuthClientLogin{}
-		if err := a.parseMessage(c, msg, "GameAuthClientLogin", &clientLoginPkt); err == nil {
-			a.HandleClientLogin(c, clientLoginPkt)
+		if clientLoginPkt, clientIP, err := a.clientLoginOf(msg); err == nil {
+			a.HandleClientLogin(c, clientLoginPkt, clientIP)
 		}
 	case game.GameAuthClientLogoutID:
 		clientLogoutPkt := game.GameAuthClientLogout{}
@@ -116,6 +189,21 @@ func (a *GameHandler) HandleMessage(c *net.Client, header packets.Message, msg [
 		if err := a.parseMessage(c, msg, "GameAuthLogin", &loginPkt); err == nil {
 			a.HandleGameServerLogin(c, loginPkt)
 		}
+	case game.GameAuthChallengeResponseID:
+		responsePkt := game.GameAuthChallengeResponse{}
+		if err := a.parseMessage(c, msg, "GameAuthChallengeResponse", &responsePkt); err == nil {
+			a.HandleChallengeResponse(c, responsePkt)
+		}
+	case game.GameAuthUserCountID:
+		userCountPkt := game.GameAuthUserCount{}
+		if err := a.parseMessage(c, msg, "GameAuthUserCount", &userCountPkt); err == nil {
+			a.HandleUserCount(c, userCountPkt)
+		}
+	case game.GameAuthHeartbeatID:
+		heartbeatPkt := game.GameAuthHeartbeat{}
+		if err := a.parseMessage(c, msg, "GameAuthHeartbeat", &heartbeatPkt); err == nil {
+			a.HandleHeartbeat(c, heartbeatPkt)
+		}
 	case game.GameAuthSecurityNoCheckID:
 		securityNoPkt := game.GameAuthSecurityNoCheck{}
 		if err := a.parseMessage(c, msg, "GameAuthSecurityNoCheck", &securityNoPkt); err == nil {
@@ -129,12 +217,26 @@ func (a *GameHandler) HandleMessage(c *net.Client, header packets.Message, msg [
 	}
 }
 
-func (a *GameHandler) HandleClientKickFailed(_ *net.Client, clientKickFailedPkt game.GameAuthClientKickFailed) {
+func (a *GameHandler) HandleClientKickFailed(c *net.Client, clientKickFailedPkt game.GameAuthClientKickFailed) {
+	if !a.gameServerAuthenticated(c, "HandleClientKickFailed") {
+		c.Close()
+		return
+	}
+
 	playerName := utils.CToGoString(clientKickFailedPkt.Account[:])
+	if player := a.PlayerList.GetPlayer(playerName); player != nil && player.GameIndex != c.GameIdentifier {
+		a.Log.Error("Kick failure for player not on this server",
+			"function", "GameHandler::HandleClientKickFailed",
+			"accountName", playerName,
+			"serverIdx", c.GameIdentifier,
+			"playerServerIdx", player.GameIndex)
+		return
+	}
 	a.removePlayerFromGame(playerName)
 }
 
-func (a *GameHandler) HandleClientLogin(c *net.Client, clientLoginPkt game.GameAuthClientLogin) {
+// HandleClientLogin accepts the one-time key of a client, clientIP is empty unless the game server sent it.
+func (a *GameHandler) HandleClientLogin(c *net.Client, clientLoginPkt game.GameAuthClientLogin, clientIP string) {
 	if !a.gameServerAuthenticated(c, "HandleClientLogin") {
 		c.Close()
 		return
@@ -166,27 +268,31 @@ func (a *GameHandler) HandleClientLogin(c *net.Client, clientLoginPkt game.GameA
 		return
 	}
 
-	if player.OneTimeKey != clientLoginPkt.OneTimeKey {
-		a.Log.Error("Client tried to login with wrong key",
+	valid, reason := a.PlayerList.ConfirmOneTimeKey(player, currGame.ServerIdx, clientLoginPkt.OneTimeKey,
+		clientIP, a.Config.Security.OneTimeKey.BindIP)
+	if !valid {
+		a.Log.Error("Client login with invalid key",
 			"function", "GameHandler::HandleClientLogin",
 			"accountID", player.AccountID,
 			"accountName", player.AccountName,
-			"expectedKey", player.OneTimeKey,
+			"reason", reason,
+			"clientIP", clientIP,
 			"receivedKey", clientLoginPkt.OneTimeKey)
+		a.audit(model.AuditEventGameLogin, player, currGame.ServerIdx, 0, reason)
 		c.Send(loginResultPkt, game.AuthGameClientLoginID)
 		return
 	}
 
-	player.GameIndex = currGame.ServerIdx
-	player.IsInGame = true
+	if account, found := a.DB.GetUserByID(player.AccountID); found {
+		loginResultPkt.ContinuousPlayTime, loginResultPkt.ContinuousLogoutTime =
+			continuousPlayTime(account, a.Config.Fatigue.ResetMinutes, player.GameLoginAt)
+	}
 	loginResultPkt.Result = packets.ResultSuccess
 	loginResultPkt.AccountID = player.AccountID
 	loginResultPkt.Permission = player.Permission
-	loginResultPkt.PCBangUser = 0
+	loginResultPkt.PCBangUser = a.pcBangStatus(player)
 	loginResultPkt.Age = uint32(player.Age)
-	loginResultPkt.EventCode = 0
-	loginResultPkt.ContinuousPlayTime = 0
-	loginResultPkt.ContinuousLogoutTime = 0
+	loginResultPkt.EventCode, _ = a.DB.GetActiveEventCode(player.AccountID)
 	if succ, err := a.DB.UpdateLastLoginServerIdx(player.AccountID, player.GameIndex); !succ {
 		a.Log.Error("Cannot update Last Login ServerIdx",
 			"function", "GameHandler::HandleClientLogin",
@@ -195,6 +301,7 @@ func (a *GameHandler) HandleClientLogin(c *net.Client, clientLoginPkt game.GameA
 			"error", err.Error())
 	}
 
+	a.audit(model.AuditEventGameLogin, player, currGame.ServerIdx, 0, "success")
 	c.Send(loginResultPkt, game.AuthGameClientLoginID)
 }
 
@@ -205,17 +312,76 @@ func (a *GameHandler) HandleClientLogout(c *net.Client, clientLogoutPkt game.Gam
 	}
 
 	playerName := utils.CToGoString(clientLogoutPkt.Account[:])
+	if player := a.PlayerList.GetPlayer(playerName); player != nil {
+		if player.GameIndex != c.GameIdentifier {
+			a.Log.Error("Logout of player not on this server",
+				"function", "GameHandler::HandleClientLogout",
+				"accountName", playerName,
+				"serverIdx", c.GameIdentifier,
+				"playerServerIdx", player.GameIndex)
+			return
+		}
+		a.audit(model.AuditEventLogout, player, c.GameIdentifier, clientLogoutPkt.ContinuousPlayTime, "")
+		a.savePlayTime(player, clientLogoutPkt.ContinuousPlayTime)
+	}
 	a.removePlayerFromGame(playerName)
 }
 
-func (a *GameHandler) HandleSecurityNoCheck(c *net.Client, _ game.GameAuthSecurityNoCheck) {
+func (a *GameHandler) HandleUserCount(c *net.Client, userCountPkt game.GameAuthUserCount) {
+	if !a.gameServerAuthenticated(c, "HandleUserCount") {
+		c.Close()
+		return
+	}
+
+	if !a.Config.Server.AuthGame.AcceptLoadReports {
+		a.Log.Debug("Ignoring load report",
+			"function", "GameHandler::HandleUserCount",
+			"serverIdx", c.GameIdentifier)
+		return
+	}
+	a.List.UpdateLoad(c.GameIdentifier, userCountPkt.UserCount, userCountPkt.Capacity)
+}
+
+func (a *GameHandler) HandleSecurityNoCheck(c *net.Client, securityPkt game.GameAuthSecurityNoCheck) {
 	if !a.gameServerAuthenticated(c, "HandleSecurityNoCheck") {
 		c.Close()
 		return
 	}
+
+	resultPkt := game.AuthGameSecurityNoCheck{
+		Account: securityPkt.Account,
+		Result:  packets.ResultAccessDenied,
+	}
+	playerName := utils.CToGoString(securityPkt.Account[:])
+	player := a.PlayerList.GetPlayer(playerName)
+	if player == nil || !player.IsInGame || player.GameIndex != c.GameIdentifier {
+		a.Log.Error("Security code check for player not on this server",
+			"function", "GameHandler::HandleSecurityNoCheck",
+			"accountName", playerName,
+			"serverIdx", c.GameIdentifier)
+		c.Send(resultPkt, game.AuthGameSecurityNoCheckID)
+		return
+	}
+
+	resultPkt.Result = a.verifySecurityCode(player, utils.CToGoString(securityPkt.Security[:]))
+	c.Send(resultPkt, game.AuthGameSecurityNoCheckID)
 }
 
 func (a *GameHandler) HandleGameServerLogin(c *net.Client, loginPkt game.GameAuthLogin) {
+	server, allowed := a.checkGameServer(c, uint32(loginPkt.ServerIdx))
+	if !allowed {
+		a.rejectGameServer(c)
+		return
+	}
+	if server.Secret != "" {
+		a.sendChallenge(c, loginPkt, server.Secret)
+		return
+	}
+	a.registerGame(c, loginPkt)
+}
+
+// registerGame adds the game server to the list if no other server uses its ServerIdx.
+func (a *GameHandler) registerGame(c *net.Client, loginPkt game.GameAuthLogin) {
 	srv := Game{
 		Client:              c,
 		ServerIdx:           uint32(loginPkt.ServerIdx),
@@ -234,11 +400,7 @@ func (a *GameHandler) HandleGameServerLogin(c *net.Client, loginPkt game.GameAut
 			"serverPort", srv.ServerPort,
 			"serverScreenshotURL", srv.ServerScreenshotURL,
 			"isAdultServer", srv.IsAdultServer)
-		resultPkt := game.AuthGameLoginResult{
-			Result: packets.ResultAccessDenied,
-		}
-		c.Send(resultPkt, game.AuthGameLoginResultID)
-		c.Close()
+		a.rejectGameServer(c)
 		return
 	}
 
@@ -276,7 +438,11 @@ func (a *GameHandler) removePlayerFromGame(playerName string) {
 			"accountName", playerName)
 		return
 	}
+	serverIdx, wasInGame := player.GameIndex, player.IsInGame
 	a.PlayerList.RemovePlayer(player)
+	if wasInGame && a.slotFreed != nil {
+		a.slotFreed(serverIdx)
+	}
 }
 
 func (a *GameHandler) gameServerAuthenticated(c *net.Client, funcName string) bool {
@@ -297,3 +463,16 @@ func (a *GameHandler) gameServerAuthenticated(c *net.Client, funcName string) bo
 
 	return true
 }
+
+// audit records an event reported by a game server in the login audit trail.
+func (a *GameHandler) audit(event string, player *Player, serverIdx, playTime uint32, detail string) {
+	a.Audit.Record(model.LoginAudits{
+		AccountID:   player.AccountID,
+		AccountName: player.AccountName,
+		IP:          player.IP,
+		Event:       event,
+		Detail:      detail,
+		ServerIdx:   serverIdx,
+		PlayTime:    playTime,
+	})
+}
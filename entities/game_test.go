@@ -0,0 +1,32 @@
+package entities_test
+
+import (
+	"mononoke-go/config"
+	"mononoke-go/entities"
+	"mononoke-go/net/packets/game"
+	"testing"
+)
+
+func TestClientLogoutOnlyFromHostingServer(t *testing.T) {
+	handler := newTestAuthHandler(new(config.Configuration))
+	handler.Games.DB = newTestDB(t)
+	player := addTestPlayer(handler, "alice", 1, true)
+	logoutPkt := game.GameAuthClientLogout{}
+	copy(logoutPkt.Account[:], "alice")
+
+	otherServer, _ := newTestClient(t)
+	otherServer.IsAuthenticated, otherServer.GameIdentifier = true, 2
+	handler.GameSrvs.AddGame(&entities.Game{Client: otherServer, ServerIdx: 2})
+	handler.Games.HandleClientLogout(otherServer, logoutPkt)
+	if handler.Players.GetPlayer("alice") != player {
+		t.Fatal("player removed by the logout of a server not hosting it")
+	}
+
+	hostingServer, _ := newTestClient(t)
+	hostingServer.IsAuthenticated, hostingServer.GameIdentifier = true, 1
+	handler.GameSrvs.AddGame(&entities.Game{Client: hostingServer, ServerIdx: 1})
+	handler.Games.HandleClientLogout(hostingServer, logoutPkt)
+	if handler.Players.GetPlayer("alice") != nil {
+		t.Error("player still listed after the logout of the hosting server")
+	}
+}
@@ -0,0 +1,106 @@
+package entities
+
+import (
+	"mononoke-go/config"
+	"mononoke-go/net"
+	"mononoke-go/net/packets"
+	"mononoke-go/net/packets/game"
+	"mononoke-go/utils"
+	"time"
+)
+
+const gameChallengeTimeout = 10 * time.Second
+
+// pendingGameLogin is a game server login waiting for the answer to its challenge.
+type pendingGameLogin struct {
+	Login     game.GameAuthLogin
+	Challenge [utils.ChallengeSize]byte
+	Secret    string
+}
+
+// checkGameServer verifies that the game server may register from the IP of the connection.
+func (a *GameHandler) checkGameServer(c *net.Client, serverIdx uint32) (config.GameServer, bool) {
+	server, configured := a.Config.GetGameServer(serverIdx)
+	if !configured && a.Config.Server.AuthGame.RequireRegistration {
+		a.Log.Warn("Rejected unregistered game server",
+			"function", "GameHandler::checkGameServer",
+			"serverIdx", serverIdx,
+			"remoteAddr", c.GetEndpoint())
+		return server, false
+	}
+	if len(server.AllowedIPs) == 0 {
+		return server, true
+	}
+
+	ip := c.GetIP()
+	for _, allowed := range server.AllowedIPs {
+		prefix, err := utils.ParseIPOrCIDR(allowed)
+		if err != nil {
+			a.Log.Warn("Invalid allowed IP of game server",
+				"function", "GameHandler::checkGameServer",
+				"serverIdx", serverIdx,
+				"allowedIP", allowed,
+				"error", err.Error())
+			continue
+		}
+		if utils.PrefixContainsIP(prefix, ip) {
+			return server, true
+		}
+	}
+	a.Log.Warn("Rejected game server from unknown IP",
+		"function", "GameHandler::checkGameServer",
+		"serverIdx", serverIdx,
+		"ip", ip)
+	return server, false
+}
+
+// sendChallenge asks the game server to prove that it knows the secret before it gets registered.
+func (a *GameHandler) sendChallenge(c *net.Client, loginPkt game.GameAuthLogin, secret string) {
+	challenge, err := utils.GenerateChallenge()
+	if err != nil {
+		a.Log.Error("Cannot generate challenge",
+			"function", "GameHandler::sendChallenge",
+			"error", err.Error())
+		a.rejectGameServer(c)
+		return
+	}
+
+	a.pending.Store(c, pendingGameLogin{Login: loginPkt, Challenge: challenge, Secret: secret})
+	time.AfterFunc(gameChallengeTimeout, func() {
+		if _, waiting := a.pending.LoadAndDelete(c); waiting {
+			a.Log.Warn("Game server didn't answer the challenge",
+				"function", "GameHandler::sendChallenge",
+				"serverIdx", loginPkt.ServerIdx,
+				"remoteAddr", c.GetEndpoint())
+			a.rejectGameServer(c)
+		}
+	})
+	c.Send(game.AuthGameChallenge{Challenge: challenge}, game.AuthGameChallengeID)
+}
+
+func (a *GameHandler) HandleChallengeResponse(c *net.Client, responsePkt game.GameAuthChallengeResponse) {
+	value, waiting := a.pending.LoadAndDelete(c)
+	if !waiting {
+		a.Log.Error("Unexpected challenge response",
+			"function", "GameHandler::HandleChallengeResponse",
+			"remoteAddr", c.GetEndpoint())
+		c.Close()
+		return
+	}
+
+	pending, _ := value.(pendingGameLogin)
+	if !utils.VerifyChallengeResponse(pending.Secret, pending.Challenge, pending.Login.ServerIdx, responsePkt.Response) {
+		a.Log.Warn("Game server sent a wrong challenge response",
+			"function", "GameHandler::HandleChallengeResponse",
+			"serverIdx", pending.Login.ServerIdx,
+			"remoteAddr", c.GetEndpoint())
+		a.rejectGameServer(c)
+		return
+	}
+	a.registerGame(c, pending.Login)
+}
+
+func (a *GameHandler) rejectGameServer(c *net.Client) {
+	c.Send(game.AuthGameLoginResult{Result: packets.ResultAccessDenied}, game.AuthGameLoginResultID)
+	c.Close()
+}
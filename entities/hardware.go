@@ -0,0 +1,156 @@
+package entities
+
+import (
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"mononoke-go/database"
+	"mononoke-go/model"
+	"strings"
+	"time"
+)
+
+const macStampLength = 8
+
+var (
+	ErrInvalidMacStamp     = errors.New("MacStamp has to be 16 hexadecimal characters")
+	ErrHardwareBanNotFound = errors.New("MacStamp is not banned")
+)
+
+// SharedMacStamp lists the accounts which logged in from the same machine.
+type SharedMacStamp struct {
+	MacStamp string            `json:"macStamp"`
+	Accounts []MacStampAccount `json:"accounts"`
+}
+
+type MacStampAccount struct {
+	AccountName string    `json:"accountName"`
+	IP          string    `json:"ip"`
+	Logins      uint32    `json:"logins"`
+	LastSeenAt  time.Time `json:"lastSeenAt"`
+}
+
+// HardwareService manages MacStamp bans for the admin API and the CLI.
+type HardwareService struct {
+	DB  *database.GormDatabase
+	Log *slog.Logger
+}
+
+func (s *HardwareService) Ban(macStamp, reason, createdBy string, expiresAt *time.Time) error {
+	normalized, err := NormalizeMacStamp(macStamp)
+	if err != nil {
+		return err
+	}
+	err = s.DB.SaveHardwareBan(&model.HardwareBans{
+		MacStamp:  normalized,
+		Reason:    reason,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+	s.Log.Info("MacStamp banned",
+		"function", "HardwareService::Ban",
+		"macStamp", normalized,
+		"reason", reason,
+		"expiresAt", expiresAt)
+	return nil
+}
+
+func (s *HardwareService) Unban(macStamp string) error {
+	normalized, err := NormalizeMacStamp(macStamp)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.DB.DeleteHardwareBan(normalized)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrHardwareBanNotFound
+	}
+	s.Log.Info("MacStamp unbanned",
+		"function", "HardwareService::Unban",
+		"macStamp", normalized)
+	return nil
+}
+
+// SharedMacStamps reports the MacStamps used by at least minAccounts accounts.
+func (s *HardwareService) SharedMacStamps(minAccounts int) ([]SharedMacStamp, error) {
+	macStamps, err := s.DB.GetSharedMacStamps(max(minAccounts, 2))
+	if err != nil || len(macStamps) == 0 {
+		return nil, err
+	}
+	entries, accounts, err := s.DB.GetMacStampAccounts(macStamps)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uint32]string, len(accounts))
+	for _, account := range accounts {
+		names[account.AccountID] = account.AccountName
+	}
+	shared := make([]SharedMacStamp, 0, len(macStamps))
+	for _, macStamp := range macStamps {
+		report := SharedMacStamp{MacStamp: macStamp}
+		for _, entry := range entries {
+			if entry.MacStamp == macStamp {
+				report.Accounts = append(report.Accounts, MacStampAccount{
+					AccountName: names[entry.AccountID],
+					IP:          entry.IP,
+					Logins:      entry.Logins,
+					LastSeenAt:  entry.LastSeenAt,
+				})
+			}
+		}
+		shared = append(shared, report)
+	}
+	return shared, nil
+}
+
+// NormalizeMacStamp validates a MacStamp entered by an admin and converts it to lower case.
+func NormalizeMacStamp(macStamp string) (string, error) {
+	decoded, err := hex.DecodeString(strings.TrimSpace(macStamp))
+	if err != nil || len(decoded) != macStampLength {
+		return "", ErrInvalidMacStamp
+	}
+	return hex.EncodeToString(decoded), nil
+}
+
+// macStampString converts the MacStamp sent by the client, clients before 9.6.6 send none.
+func macStampString(macStamp [macStampLength]byte) string {
+	if macStamp == [macStampLength]byte{} {
+		return ""
+	}
+	return hex.EncodeToString(macStamp[:])
+}
+
+// checkHardwareBan returns true if the player logs in from a banned machine.
+func (a *AuthHandler) checkHardwareBan(player *Player) bool {
+	if player.MacStamp == "" {
+		return false
+	}
+	ban, banned := a.DB.GetActiveHardwareBan(player.MacStamp)
+	if banned {
+		a.Log.Info("Login rejected, MacStamp is banned",
+			"function", "AuthHandler::checkHardwareBan",
+			"accountName", player.AccountName,
+			"macStamp", player.MacStamp,
+			"reason", ban.Reason)
+	}
+	return banned
+}
+
+func (a *AuthHandler) recordMacStamp(player *Player) {
+	if player.MacStamp == "" {
+		return
+	}
+	if err := a.DB.RecordMacStamp(player.AccountID, player.MacStamp, player.IP, time.Now()); err != nil {
+		a.Log.Error("Cannot record MacStamp",
+			"function", "AuthHandler::recordMacStamp",
+			"accountName", player.AccountName,
+			"error", err.Error())
+	}
+}
@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"mononoke-go/net"
+	"mononoke-go/net/packets/game"
+	"time"
+)
+
+// MonitorHeartbeats pings the registered game servers and drops those which stay silent longer than the timeout.
+func (a *GameHandler) MonitorHeartbeats(interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var sequence uint32
+	for range ticker.C {
+		now := time.Now()
+		for _, stale := range a.List.Stale(now.Add(-timeout)) {
+			srv := stale.Game
+			a.Log.Warn("Game server unhealthy, removing it from the server list",
+				"function", "GameHandler::MonitorHeartbeats",
+				"serverIdx", srv.ServerIdx,
+				"serverName", srv.ServerName,
+				"remoteAddr", srv.Client.GetEndpoint(),
+				"lastSeen", stale.LastSeen,
+				"silentFor", now.Sub(stale.LastSeen).Round(time.Second).String())
+			a.List.RemoveGame(srv)
+			a.gameServerLost(srv.ServerIdx)
+			srv.Client.Close()
+		}
+
+		sequence++
+		heartbeatPkt := game.AuthGameHeartbeat{Sequence: sequence}
+		for _, srv := range a.List.All() {
+			srv.Client.Send(heartbeatPkt, game.AuthGameHeartbeatID)
+		}
+	}
+}
+
+func (a *GameHandler) HandleHeartbeat(c *net.Client, heartbeatPkt game.GameAuthHeartbeat) {
+	a.Log.Debug("Heartbeat received",
+		"function", "GameHandler::HandleHeartbeat",
+		"serverIdx", c.GameIdentifier,
+		"sequence", heartbeatPkt.Sequence)
+}
@@ -0,0 +1,39 @@
+package entities_test
+
+import (
+	"io"
+	"log/slog"
+	"mononoke-go/config"
+	"mononoke-go/database"
+	"mononoke-go/entities"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestDB(t *testing.T) *database.GormDatabase {
+	t.Helper()
+	db, err := database.New("sqlite3", filepath.Join(t.TempDir(), "test.db"), "test", "unused", true)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(db.Close)
+	return db
+}
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newTestAuthHandler(conf *config.Configuration) *entities.AuthHandler {
+	players := &entities.PlayerList{Players: make(map[string]*entities.Player)}
+	games := &entities.GameList{Games: make(map[uint32]*entities.Game)}
+	return &entities.AuthHandler{
+		GameSrvs: games,
+		Games:    &entities.GameHandler{List: games, PlayerList: players, Config: conf, Log: newTestLogger()},
+		Players:  players,
+		Queue:    entities.NewLoginQueue(time.Duration(conf.Queue.TimeoutSeconds) * time.Second),
+		Config:   conf,
+		Log:      newTestLogger(),
+	}
+}
@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"errors"
+	"mononoke-go/model"
+	"mononoke-go/utils"
+	"time"
+)
+
+var ErrLauncherDisabled = errors.New("launcher login is disabled")
+
+// IssueLauncherToken creates a single-use token the client can send instead of the password.
+func (s *AccountService) IssueLauncherToken(name string) (string, time.Time, error) {
+	if !s.Config.Launcher.Enabled {
+		return "", time.Time{}, ErrLauncherDisabled
+	}
+	account, err := s.Get(name)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	token, err := utils.GenerateLauncherToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	validity := time.Duration(s.Config.Launcher.TokenSeconds) * time.Second
+	if err = s.issueToken(account, model.TokenPurposeLauncher, token, validity); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, time.Now().Add(validity), nil
+}
+
+// launcherLogin checks if the password is an unused launcher token of the account.
+// Launcher tokens replace the password, enrolled accounts still need their second factor.
+func (a *AuthHandler) launcherLogin(accountName, password string) (*model.Accounts, bool) {
+	if !a.Config.Launcher.Enabled || len(password) != utils.LauncherTokenLength {
+		return nil, false
+	}
+	account, found := a.DB.GetUserByName(accountName)
+	if !found || !a.DB.UseAccountTokenOf(account.AccountID, model.TokenPurposeLauncher,
+		utils.HashToken(password), time.Now()) {
+		return nil, false
+	}
+	a.Log.Debug("Login with launcher token",
+		"function", "AuthHandler::launcherLogin",
+		"accountName", accountName)
+	return account, true
+}
@@ -0,0 +1,118 @@
+package entities
+
+import (
+	"mononoke-go/model"
+	"mononoke-go/net/packets"
+	"time"
+)
+
+// checkLockout returns the result code to send if the account or the IP is currently locked.
+func (a *AuthHandler) checkLockout(accountName, ip string) (uint16, bool) {
+	if !a.Config.Security.Lockout.Enabled {
+		return packets.ResultSuccess, false
+	}
+
+	now := time.Now()
+	if lockout, found := a.DB.GetLoginLockout(model.LockoutKindIP, ip); found && lockout.LockedUntil.After(now) {
+		a.Log.Info("Login rejected, IP is locked",
+			"function", "AuthHandler::checkLockout",
+			"accountName", accountName,
+			"ip", ip,
+			"lockedUntil", lockout.LockedUntil)
+		return packets.ResultIPBlocked, true
+	}
+	if lockout, found := a.DB.GetLoginLockout(model.LockoutKindAccount, accountName); found &&
+		lockout.LockedUntil.After(now) {
+		a.Log.Info("Login rejected, account is locked",
+			"function", "AuthHandler::checkLockout",
+			"accountName", accountName,
+			"ip", ip,
+			"lockedUntil", lockout.LockedUntil)
+		return packets.ResultAccessDenied, true
+	}
+	return packets.ResultSuccess, false
+}
+
+// registerLoginFailure counts a failed login for the account and the IP and locks them if needed.
+func (a *AuthHandler) registerLoginFailure(accountName, ip string) {
+	if !a.Config.Security.Lockout.Enabled {
+		return
+	}
+
+	a.registerLockoutFailure(model.LockoutKindAccount, accountName, a.Config.Security.Lockout.AccountMaxFailures)
+	a.registerLockoutFailure(model.LockoutKindIP, ip, a.Config.Security.Lockout.IPMaxFailures)
+}
+
+// resetLoginFailures clears the failure counter of an account after a successful login.
+func (a *AuthHandler) resetLoginFailures(accountName string) {
+	if !a.Config.Security.Lockout.Enabled {
+		return
+	}
+
+	if err := a.DB.ResetLoginLockout(model.LockoutKindAccount, accountName); err != nil {
+		a.Log.Error("Cannot reset login failures",
+			"function", "AuthHandler::resetLoginFailures",
+			"accountName", accountName,
+			"error", err.Error())
+	}
+}
+
+func (a *AuthHandler) registerLockoutFailure(kind, subject string, maxFailures uint32) {
+	if maxFailures == 0 {
+		return
+	}
+
+	a.lockoutMutex.Lock()
+	defer a.lockoutMutex.Unlock()
+
+	conf := a.Config.Security.Lockout
+	now := time.Now()
+	lockout, found := a.DB.GetLoginLockout(kind, subject)
+	if !found {
+		lockout = &model.LoginLockouts{Kind: kind, Subject: subject}
+	}
+	decay := time.Duration(conf.DecaySeconds) * time.Second
+	if decay > 0 && lockout.LockCount > 0 && now.Sub(lockout.LockedUntil) > decay {
+		lockout.LockCount = 0
+	}
+
+	window := time.Duration(conf.WindowSeconds) * time.Second
+	if lockout.Failures == 0 || now.Sub(lockout.WindowStart) > window {
+		lockout.Failures = 0
+		lockout.WindowStart = now
+	}
+	lockout.Failures++
+
+	if lockout.Failures >= maxFailures {
+		lockout.LockedUntil = now.Add(lockoutDuration(conf.LockSeconds, conf.MaxLockSeconds, lockout.LockCount))
+		lockout.LockCount++
+		lockout.Failures = 0
+		a.Log.Warn("Too many failed logins, locking",
+			"function", "AuthHandler::registerLockoutFailure",
+			"kind", kind,
+			"subject", subject,
+			"lockCount", lockout.LockCount,
+			"lockedUntil", lockout.LockedUntil)
+	}
+
+	if err := a.DB.SaveLoginLockout(lockout); err != nil {
+		a.Log.Error("Cannot save login failure",
+			"function", "AuthHandler::registerLockoutFailure",
+			"kind", kind,
+			"subject", subject,
+			"error", err.Error())
+	}
+}
+
+// lockoutDuration doubles the lock time for every previous lock, up to the configured maximum.
+func lockoutDuration(lockSeconds, maxLockSeconds, lockCount uint32) time.Duration {
+	duration := time.Duration(lockSeconds) * time.Second
+	maxDuration := time.Duration(maxLockSeconds) * time.Second
+	for range lockCount {
+		duration *= 2
+		if duration >= maxDuration {
+			return maxDuration
+		}
+	}
+	return min(duration, maxDuration)
+}
@@ -0,0 +1,140 @@
+package entities_test
+
+import (
+	"mononoke-go/config"
+	"mononoke-go/entities"
+	"mononoke-go/model"
+	"mononoke-go/net/packets"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newLockoutHandler(t *testing.T, accountMaxFailures, ipMaxFailures uint32) *entities.AuthHandler {
+	t.Helper()
+	conf := new(config.Configuration)
+	conf.Security.Lockout.Enabled = true
+	conf.Security.Lockout.WindowSeconds = 900
+	conf.Security.Lockout.AccountMaxFailures = accountMaxFailures
+	conf.Security.Lockout.IPMaxFailures = ipMaxFailures
+	conf.Security.Lockout.LockSeconds = 60
+	conf.Security.Lockout.MaxLockSeconds = 3600
+	conf.Security.Lockout.DecaySeconds = 86400
+	handler := newTestAuthHandler(conf)
+	handler.DB = newTestDB(t)
+	return handler
+}
+
+func TestLockoutLocksAccount(t *testing.T) {
+	handler := newLockoutHandler(t, 3, 0)
+	for range 2 {
+		handler.RegisterLoginFailure("alice", "10.0.0.1")
+	}
+	if result, locked := handler.CheckLockout("alice", "10.0.0.1"); locked {
+		t.Fatalf("alice locked with result %d after 2 of 3 failures", result)
+	}
+
+	handler.RegisterLoginFailure("alice", "10.0.0.2")
+	if result, locked := handler.CheckLockout("alice", "10.0.0.3"); !locked || result != packets.ResultAccessDenied {
+		t.Errorf("CheckLockout(alice) = %d, %t, want access denied", result, locked)
+	}
+	if result, locked := handler.CheckLockout("bob", "10.0.0.1"); locked {
+		t.Errorf("bob locked with result %d by the failures of alice", result)
+	}
+}
+
+func TestLockoutLocksIP(t *testing.T) {
+	handler := newLockoutHandler(t, 0, 2)
+	handler.RegisterLoginFailure("alice", "10.0.0.1")
+	handler.RegisterLoginFailure("bob", "10.0.0.1")
+
+	if result, locked := handler.CheckLockout("carol", "10.0.0.1"); !locked || result != packets.ResultIPBlocked {
+		t.Errorf("CheckLockout(carol, 10.0.0.1) = %d, %t, want IP blocked", result, locked)
+	}
+	if result, locked := handler.CheckLockout("alice", "10.0.0.2"); locked {
+		t.Errorf("alice locked with result %d from another IP", result)
+	}
+}
+
+func TestLockoutResetOnSuccess(t *testing.T) {
+	handler := newLockoutHandler(t, 3, 0)
+	handler.RegisterLoginFailure("alice", "10.0.0.1")
+	handler.RegisterLoginFailure("alice", "10.0.0.1")
+	handler.ResetLoginFailures("alice")
+	handler.RegisterLoginFailure("alice", "10.0.0.1")
+	handler.RegisterLoginFailure("alice", "10.0.0.1")
+
+	if result, locked := handler.CheckLockout("alice", "10.0.0.1"); locked {
+		t.Errorf("alice locked with result %d although the counter was reset", result)
+	}
+}
+
+func TestLockoutConcurrentFailures(t *testing.T) {
+	handler := newLockoutHandler(t, 100, 100)
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler.RegisterLoginFailure("alice", "10.0.0.1")
+		}()
+	}
+	wg.Wait()
+
+	for kind, subject := range map[string]string{model.LockoutKindAccount: "alice", model.LockoutKindIP: "10.0.0.1"} {
+		if lockout, found := handler.DB.GetLoginLockout(kind, subject); !found || lockout.Failures != 20 {
+			t.Errorf("%s lockout = %v, want 20 failures", kind, lockout)
+		}
+	}
+}
+
+func TestLockoutCountDecays(t *testing.T) {
+	handler := newLockoutHandler(t, 0, 1)
+	err := handler.DB.SaveLoginLockout(&model.LoginLockouts{
+		Kind:        model.LockoutKindIP,
+		Subject:     "10.0.0.1",
+		LockCount:   5,
+		LockedUntil: time.Now().Add(-48 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	start := time.Now()
+	handler.RegisterLoginFailure("alice", "10.0.0.1")
+	lockout, found := handler.DB.GetLoginLockout(model.LockoutKindIP, "10.0.0.1")
+	if !found || lockout.LockCount != 1 {
+		t.Fatalf("lockout = %v, want the lock count to start over", lockout)
+	}
+	if locked := lockout.LockedUntil.Sub(start); locked > 2*time.Minute {
+		t.Errorf("IP locked for %s after a quiet day, want the first lock duration", locked.Round(time.Second))
+	}
+}
+
+func TestLockoutDisabled(t *testing.T) {
+	handler := newLockoutHandler(t, 1, 1)
+	handler.Config.Security.Lockout.Enabled = false
+	handler.RegisterLoginFailure("alice", "10.0.0.1")
+
+	if result, locked := handler.CheckLockout("alice", "10.0.0.1"); locked {
+		t.Errorf("alice locked with result %d although lockouts are disabled", result)
+	}
+}
+
+func TestLockoutDuration(t *testing.T) {
+	tests := []struct {
+		lockCount uint32
+		want      time.Duration
+	}{
+		{0, time.Minute},
+		{1, 2 * time.Minute},
+		{3, 8 * time.Minute},
+		{6, time.Hour},
+		{100, time.Hour},
+	}
+	for _, test := range tests {
+		if got := entities.LockoutDuration(60, 3600, test.lockCount); got != test.want {
+			t.Errorf("LockoutDuration(60, 3600, %d) = %s, want %s", test.lockCount, got, test.want)
+		}
+	}
+}
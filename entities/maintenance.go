@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"slices"
+)
+
+// maintenanceFor returns the message of a maintenance affecting a player with the given permission.
+// Without checkServer only maintenances of all servers are considered.
+func (a *AuthHandler) maintenanceFor(permission, serverIdx uint32, checkServer bool) (string, bool) {
+	conf := a.Config.Maintenance
+	if permission >= conf.BypassPermission {
+		return "", false
+	}
+
+	if conf.Enabled && (len(conf.ServerIdx) == 0 || (checkServer && slices.Contains(conf.ServerIdx, serverIdx))) {
+		return conf.Message, true
+	}
+
+	maintenances, err := a.DB.GetMaintenances()
+	if err != nil {
+		a.Log.Error("Cannot load maintenances",
+			"function", "AuthHandler::maintenanceFor",
+			"error", err.Error())
+		return "", false
+	}
+	for _, maintenance := range maintenances {
+		if maintenance.AllServers || (checkServer && maintenance.ServerIdx == serverIdx) {
+			if maintenance.Message == "" {
+				return conf.Message, true
+			}
+			return maintenance.Message, true
+		}
+	}
+	return "", false
+}
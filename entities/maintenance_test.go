@@ -0,0 +1,73 @@
+package entities_test
+
+import (
+	"mononoke-go/config"
+	"testing"
+)
+
+func TestMaintenanceFromConfiguration(t *testing.T) {
+	conf := new(config.Configuration)
+	conf.Maintenance.Enabled = true
+	conf.Maintenance.ServerIdx = []uint32{2}
+	conf.Maintenance.BypassPermission = 100
+	conf.Maintenance.Message = "maintenance"
+	handler := newTestAuthHandler(conf)
+	handler.DB = newTestDB(t)
+
+	if message, active := handler.MaintenanceFor(0, 2, true); !active || message != "maintenance" {
+		t.Errorf("MaintenanceFor(0, 2) = %q, %t, want the configured message", message, active)
+	}
+	if _, active := handler.MaintenanceFor(0, 1, true); active {
+		t.Error("maintenance of server 2 affects server 1")
+	}
+	if _, active := handler.MaintenanceFor(0, 2, false); active {
+		t.Error("maintenance of server 2 rejects the account login")
+	}
+	if _, active := handler.MaintenanceFor(100, 2, true); active {
+		t.Error("maintenance not bypassed with permission 100")
+	}
+
+	conf.Maintenance.ServerIdx = nil
+	if _, active := handler.MaintenanceFor(0, 0, false); !active {
+		t.Error("maintenance of all servers does not reject the account login")
+	}
+}
+
+func TestMaintenanceFromDatabase(t *testing.T) {
+	conf := new(config.Configuration)
+	conf.Maintenance.BypassPermission = 100
+	conf.Maintenance.Message = "maintenance"
+	handler := newTestAuthHandler(conf)
+	handler.DB = newTestDB(t)
+
+	if _, active := handler.MaintenanceFor(0, 1, true); active {
+		t.Fatal("maintenance active without entries")
+	}
+
+	if err := handler.DB.StartMaintenance(false, 1, ""); err != nil {
+		t.Fatal(err.Error())
+	}
+	if message, active := handler.MaintenanceFor(0, 1, true); !active || message != "maintenance" {
+		t.Errorf("MaintenanceFor(0, 1) = %q, %t, want the default message", message, active)
+	}
+	if _, active := handler.MaintenanceFor(0, 1, false); active {
+		t.Error("maintenance of server 1 rejects the account login")
+	}
+
+	if err := handler.DB.StartMaintenance(true, 0, "back soon"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if message, active := handler.MaintenanceFor(0, 0, false); !active || message != "back soon" {
+		t.Errorf("MaintenanceFor(0, 0) = %q, %t, want the message of the maintenance", message, active)
+	}
+	if _, active := handler.MaintenanceFor(100, 1, true); active {
+		t.Error("maintenance not bypassed with permission 100")
+	}
+
+	if err := handler.DB.StopAllMaintenances(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, active := handler.MaintenanceFor(0, 1, true); active {
+		t.Error("maintenance still active after stopping all")
+	}
+}
@@ -0,0 +1,158 @@
+package entities
+
+import (
+	"encoding/binary"
+	"mononoke-go/net"
+	"mononoke-go/net/packets"
+	"mononoke-go/net/packets/client"
+	"mononoke-go/net/packets/game"
+	"mononoke-go/utils"
+	"time"
+)
+
+var clientLoginWithIPSize = binary.Size(game.GameAuthClientLoginWithIP{})
+
+// setOneTimeKey puts the key and the pending time into the answer, clients since 8.1.1 expect both encrypted
+// with their AES session key.
+func setOneTimeKey(c *net.Client, resultPkt *client.AuthClientSelectServer, key uint64, pendingTime uint32) error {
+	if c.SupportedVersion < packets.Version811 {
+		resultPkt.OneTimeKey = key
+		resultPkt.PendingTime = pendingTime
+		return nil
+	}
+
+	plain := binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint64(nil, key), pendingTime)
+	encrypted, err := utils.EncryptAESCBC(c.AESKey, plain)
+	if err != nil {
+		return err
+	}
+	resultPkt.EncryptedSize = int32(copy(resultPkt.EncryptedData[:], encrypted))
+	resultPkt.PendingTime = 0
+	return nil
+}
+
+// issueOneTimeKey reserves the server for the player until the game server accepts the key or it expires.
+// Returns false if the server has no free slot.
+func (a *AuthHandler) issueOneTimeKey(player *Player, serverIdx uint32, key uint64) bool {
+	expiry := time.Duration(a.Config.Security.OneTimeKey.ExpirySeconds) * time.Second
+	var expiresAt time.Time
+	if expiry > 0 {
+		expiresAt = time.Now().Add(expiry)
+	}
+	if !a.Players.ReserveServer(player, serverIdx, a.serverCapacity(serverIdx), key, expiresAt) {
+		return false
+	}
+	if expiry > 0 {
+		time.AfterFunc(expiry, func() {
+			a.expireOneTimeKey(player, key)
+		})
+	}
+	return true
+}
+
+// expireOneTimeKey releases the reservation of a player who never reached the game server.
+func (a *AuthHandler) expireOneTimeKey(player *Player, key uint64) {
+	serverIdx, released := a.Players.ReleaseReservation(player, key)
+	if !released {
+		return
+	}
+
+	a.Log.Info("One-time key expired, released server reservation",
+		"function", "AuthHandler::expireOneTimeKey",
+		"accountName", player.AccountName,
+		"serverIdx", serverIdx)
+	a.admitQueued(serverIdx)
+}
+
+// ReserveServer stores the one-time key of the player, the player counts as in game until the key is released.
+// Returns false if the server already holds capacity players, 0 is unlimited.
+func (pl *PlayerList) ReserveServer(
+	player *Player, serverIdx, capacity uint32, key uint64, expiresAt time.Time,
+) bool {
+	pl.mutex.Lock()
+	defer pl.mutex.Unlock()
+	if capacity > 0 && pl.countInGame(serverIdx) >= capacity {
+		return false
+	}
+
+	player.IsInGame = true
+	player.IsConfirmedInGame = false
+	player.GameIndex = serverIdx
+	player.OneTimeKey = key
+	player.OneTimeKeyExpiresAt = expiresAt
+	return true
+}
+
+// ReleaseReservation drops the reservation of the key, unless the game server accepted it in the meantime.
+// Players who already left the auth server are removed. Returns the server of the released reservation.
+func (pl *PlayerList) ReleaseReservation(player *Player, key uint64) (uint32, bool) {
+	pl.mutex.Lock()
+	defer pl.mutex.Unlock()
+	if pl.Players[player.AccountName] != player || player.IsConfirmedInGame || player.OneTimeKey != key {
+		return 0, false
+	}
+
+	pl.release(player)
+	return player.GameIndex, true
+}
+
+// release must be called with the mutex held.
+func (pl *PlayerList) release(player *Player) {
+	player.OneTimeKey = 0
+	player.IsInGame = false
+	if player.AuthDisconnected {
+		pl.remove(player)
+	}
+}
+
+// ConfirmOneTimeKey checks the one-time key presented by a game server and marks the player as in game on
+// success, every key can be used once. Returns the reason if the key is rejected.
+func (pl *PlayerList) ConfirmOneTimeKey(
+	player *Player, serverIdx uint32, key uint64, clientIP string, bindIP bool,
+) (bool, string) {
+	pl.mutex.Lock()
+	defer pl.mutex.Unlock()
+	if player.OneTimeKey == 0 || player.IsConfirmedInGame {
+		return false, "one-time key already used"
+	}
+	if player.OneTimeKey != key {
+		return false, "wrong one-time key"
+	}
+	if player.GameIndex != serverIdx {
+		return false, "one-time key issued for another server"
+	}
+	if !player.OneTimeKeyExpiresAt.IsZero() && time.Now().After(player.OneTimeKeyExpiresAt) {
+		return false, "one-time key expired"
+	}
+	if bindIP && clientIP == "" {
+		return false, "client IP not forwarded by the game server"
+	}
+	if bindIP && clientIP != player.IP {
+		return false, "one-time key used from another IP"
+	}
+
+	player.IsInGame = true
+	player.IsConfirmedInGame = true
+	player.OneTimeKey = 0
+	player.GameLoginAt = time.Now()
+	return true, ""
+}
+
+// clientLoginOf parses GameAuthClientLogin, with the client IP if the game server appended it.
+func (a *GameHandler) clientLoginOf(msg []byte) (game.GameAuthClientLogin, string, error) {
+	if len(msg) >= clientLoginWithIPSize {
+		clientLoginPkt := game.GameAuthClientLoginWithIP{}
+		if err := a.parseMessage(nil, msg, "GameAuthClientLoginWithIP", &clientLoginPkt); err != nil {
+			return game.GameAuthClientLogin{}, "", err
+		}
+		return game.GameAuthClientLogin{
+			Header:     clientLoginPkt.Header,
+			Account:    clientLoginPkt.Account,
+			OneTimeKey: clientLoginPkt.OneTimeKey,
+		}, utils.CToGoString(clientLoginPkt.ClientIP[:]), nil
+	}
+
+	clientLoginPkt := game.GameAuthClientLogin{}
+	err := a.parseMessage(nil, msg, "GameAuthClientLogin", &clientLoginPkt)
+	return clientLoginPkt, "", err
+}
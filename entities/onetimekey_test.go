@@ -0,0 +1,95 @@
+package entities_test
+
+import (
+	"mononoke-go/config"
+	"testing"
+	"time"
+)
+
+func TestConfirmOneTimeKey(t *testing.T) {
+	handler := newTestAuthHandler(new(config.Configuration))
+	player := addTestPlayer(handler, "alice", 0, false)
+	player.IP = "10.0.0.1"
+	handler.Players.ReserveServer(player, 1, 0, 42, time.Time{})
+
+	tests := []struct {
+		key      uint64
+		clientIP string
+		bindIP   bool
+		valid    bool
+	}{
+		{43, "10.0.0.1", false, false},
+		{42, "", true, false},
+		{42, "10.0.0.2", true, false},
+		{42, "10.0.0.1", true, true},
+		{42, "10.0.0.1", true, false},
+	}
+	for _, test := range tests {
+		valid, reason := handler.Players.ConfirmOneTimeKey(player, 1, test.key, test.clientIP, test.bindIP)
+		if valid != test.valid {
+			t.Errorf("ConfirmOneTimeKey(%d, %q, %t) = %t (%s), want %t",
+				test.key, test.clientIP, test.bindIP, valid, reason, test.valid)
+		}
+	}
+	if !player.IsConfirmedInGame || player.OneTimeKey != 0 {
+		t.Error("player not confirmed in game")
+	}
+	if _, released := handler.Players.ReleaseReservation(player, 42); released {
+		t.Error("reservation released after the game server accepted the key")
+	}
+}
+
+func TestConfirmOneTimeKeyOfAnotherServer(t *testing.T) {
+	handler := newTestAuthHandler(new(config.Configuration))
+	player := addTestPlayer(handler, "alice", 0, false)
+	handler.Players.ReserveServer(player, 1, 0, 42, time.Time{})
+
+	if valid, _ := handler.Players.ConfirmOneTimeKey(player, 2, 42, "", false); valid {
+		t.Fatal("key issued for server 1 accepted by server 2")
+	}
+	if player.GameIndex != 1 || player.IsConfirmedInGame || player.OneTimeKey != 42 {
+		t.Error("key consumed or reservation changed by server 2")
+	}
+	if valid, reason := handler.Players.ConfirmOneTimeKey(player, 1, 42, "", false); !valid {
+		t.Errorf("key rejected by server 1 after the attempt of server 2: %s", reason)
+	}
+}
+
+func TestConfirmOneTimeKeyExpired(t *testing.T) {
+	handler := newTestAuthHandler(new(config.Configuration))
+	player := addTestPlayer(handler, "alice", 0, false)
+	handler.Players.ReserveServer(player, 1, 0, 42, time.Now().Add(-time.Second))
+
+	if valid, _ := handler.Players.ConfirmOneTimeKey(player, 1, 42, "", false); valid {
+		t.Error("expired key accepted")
+	}
+}
+
+func TestReleaseReservation(t *testing.T) {
+	handler := newTestAuthHandler(new(config.Configuration))
+	alice := addTestPlayer(handler, "alice", 0, false)
+	handler.Players.ReserveServer(alice, 1, 0, 42, time.Time{})
+
+	if _, released := handler.Players.ReleaseReservation(alice, 43); released {
+		t.Error("reservation released with another key")
+	}
+	if serverIdx, released := handler.Players.ReleaseReservation(alice, 42); !released || serverIdx != 1 {
+		t.Errorf("ReleaseReservation(alice) = %d, %t, want server 1", serverIdx, released)
+	}
+	if alice.IsInGame || handler.Players.GetPlayer("alice") != alice {
+		t.Error("alice still in game or removed although connected")
+	}
+
+	bob := addTestPlayer(handler, "bob", 0, false)
+	handler.Players.ReserveServer(bob, 1, 0, 7, time.Time{})
+	handler.Players.Disconnect(bob)
+	if handler.Players.GetPlayer("bob") != bob {
+		t.Fatal("bob removed on disconnect while holding a reservation")
+	}
+	if _, released := handler.Players.ReleaseReservation(bob, 7); !released {
+		t.Error("reservation of bob not released")
+	}
+	if handler.Players.GetPlayer("bob") != nil {
+		t.Error("disconnected bob still listed after the release")
+	}
+}
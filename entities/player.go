@@ -3,7 +3,6 @@ package entities
 import (
 	"bytes"
 	"crypto/aes"
-	"crypto/cipher"
 	"crypto/des"
 	"crypto/rand"
 	"encoding/binary"
@@ -12,26 +11,39 @@ import (
 	"math/big"
 	"mononoke-go/config"
 	"mononoke-go/database"
+	"mononoke-go/model"
 	"mononoke-go/net"
 	"mononoke-go/net/packets"
 	"mononoke-go/net/packets/client"
 	"mononoke-go/utils"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 type Player struct {
 	Client          *net.Client
 	AccountID       uint32
 	AccountName     string
+	IP              string
+	MacStamp        string
 	Age             uint8
 	IsBlocked       bool
 	LastServerIndex uint32
 	IsInGame        bool
-	KickNextLogin   bool
-	OneTimeKey      uint64
-	GameIndex       uint32
-	Permission      uint32
+	// IsConfirmedInGame is set once the game server accepted the one-time key.
+	IsConfirmedInGame bool
+	KickNextLogin     bool
+	GameLoginAt       time.Time
+	OneTimeKey        uint64
+	// OneTimeKeyExpiresAt is zero if one-time keys don't expire.
+	OneTimeKeyExpiresAt time.Time
+	// AuthDisconnected is set once the client closed its connection to the auth server.
+	AuthDisconnected bool
+	GameIndex        uint32
+	Permission       uint32
+	removed          chan struct{}
 }
 
 type PlayerList struct {
@@ -41,16 +53,82 @@ type PlayerList struct {
 
 func (pl *PlayerList) AddPlayer(player *Player) {
 	pl.mutex.Lock()
+	player.removed = make(chan struct{})
 	pl.Players[player.AccountName] = player
 	pl.mutex.Unlock()
 }
 
+// RemovePlayer removes the player, unless the account is already owned by another session.
 func (pl *PlayerList) RemovePlayer(player *Player) {
 	pl.mutex.Lock()
-	delete(pl.Players, player.AccountName)
+	pl.remove(player)
 	pl.mutex.Unlock()
 }
 
+// Disconnect marks the player as gone from the auth server, players who are not in game are removed.
+func (pl *PlayerList) Disconnect(player *Player) {
+	pl.mutex.Lock()
+	player.AuthDisconnected = true
+	if !player.IsInGame {
+		pl.remove(player)
+	}
+	pl.mutex.Unlock()
+}
+
+// remove must be called with the mutex held.
+func (pl *PlayerList) remove(player *Player) {
+	if current, exists := pl.Players[player.AccountName]; exists && current == player {
+		delete(pl.Players, player.AccountName)
+		if player.removed != nil {
+			close(player.removed)
+		}
+	}
+}
+
+// WaitForRemoval blocks until the player is removed from the list or the timeout is reached.
+func (pl *PlayerList) WaitForRemoval(player *Player, timeout time.Duration) bool {
+	if player.removed == nil {
+		return false
+	}
+	select {
+	case <-player.removed:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// CountInGame counts the players which are in game or about to join the server.
+func (pl *PlayerList) CountInGame(serverIdx uint32) uint32 {
+	pl.mutex.Lock()
+	defer pl.mutex.Unlock()
+	return pl.countInGame(serverIdx)
+}
+
+// countInGame must be called with the mutex held.
+func (pl *PlayerList) countInGame(serverIdx uint32) uint32 {
+	count := uint32(0)
+	for _, player := range pl.Players {
+		if player.IsInGame && player.GameIndex == serverIdx {
+			count++
+		}
+	}
+	return count
+}
+
+// CountConfirmed counts the players which the game server confirmed to be in game.
+func (pl *PlayerList) CountConfirmed(serverIdx uint32) uint32 {
+	pl.mutex.Lock()
+	defer pl.mutex.Unlock()
+	count := uint32(0)
+	for _, player := range pl.Players {
+		if player.IsConfirmedInGame && player.GameIndex == serverIdx {
+			count++
+		}
+	}
+	return count
+}
+
 func (pl *PlayerList) GetPlayer(key string) *Player {
 	pl.mutex.Lock()
 	player := pl.Players[key]
@@ -60,22 +138,42 @@ func (pl *PlayerList) GetPlayer(key string) *Player {
 
 type AuthHandler struct {
 	GameSrvs *GameList
+	Games    *GameHandler
 	Players  *PlayerList
+	Queue    *LoginQueue
+	Bans     *BanList
+	Audit    *AuditLog
+	Auth     Authenticator
+	Registry *ServerRegistry
 	DESKey   [8]byte
 	DB       *database.GormDatabase
 	Config   *config.Configuration
 	Log      *slog.Logger
+	// lockoutMutex serializes the read-modify-write of lockout counters.
+	lockoutMutex sync.Mutex
 }
 
 func (a *AuthHandler) InitServer(server *net.Server) {
+	if a.Games != nil {
+		a.Games.slotFreed = a.admitQueued
+	}
+	server.OnAcceptConnection(func(remoteIP string) bool {
+		if reason, banned := a.Bans.IsBanned(remoteIP); banned {
+			a.Log.Info("Rejected connection from banned IP",
+				"function", "AuthHandler::OnAcceptConnection",
+				"ip", remoteIP,
+				"reason", reason)
+			return false
+		}
+		return true
+	})
 	server.OnNewMessage(func(c *net.Client, header packets.Message, message []byte) {
 		go a.HandleMessage(c, header, message)
 	})
 	server.OnClientConnectionClosed(func(c *net.Client, err error) {
-		if player := a.Players.GetPlayer(c.PlayerIdentifier); player != nil {
-			if !player.IsInGame {
-				a.Players.RemovePlayer(player)
-			}
+		if player := a.Players.GetPlayer(c.PlayerIdentifier); player != nil && player.Client == c {
+			a.Queue.Remove(player.AccountName)
+			a.Players.Disconnect(player)
 		}
 		if err != nil {
 			a.Log.Debug("Player disconnected",
@@ -177,6 +275,7 @@ func (a *AuthHandler) HandleVersion(c *net.Client, versionPkt client.ClientAuthV
 	default:
 		c.SupportedVersion = packets.Version200
 	}
+	a.audit(c, model.AuditEventVersion, nil, utils.CToGoString(versionPkt.Version[:]))
 }
 
 func (a *AuthHandler) HandleAccountLogin(c *net.Client, accountPkt client.ClientAuthAccount) {
@@ -189,79 +288,221 @@ func (a *AuthHandler) HandleAccountLogin(c *net.Client, accountPkt client.Client
 	}
 	player := new(Player)
 	player.AccountName = utils.CToGoString(accountPkt.Account)
-	// DES attempt
-	var password string
-	if len(c.AESKey) == 0 {
-		block, _ := des.NewCipher(a.DESKey[:])
-		var decryptedPassword []byte
-		encryptedBlock := make([]byte, 8)
-		for i := range 4 {
-			block.Decrypt(encryptedBlock, accountPkt.Password[i*8:(i+1)*8])
-			decryptedPassword = append(decryptedPassword, encryptedBlock...)
-		}
-		password = utils.CToGoString(decryptedPassword)
-	} else {
-		var decryptedPassword []byte
-		decryptedBlock := make([]byte, 16)
-		block, err := aes.NewCipher(c.AESKey[:16])
-		if err != nil {
-			a.Log.Error("Cannot decrypt AES password",
-				"function", "AuthHandler::HandleAccountLogin",
-				"accountName", player.AccountName,
-				"error", err.Error())
-		}
-		mode := cipher.NewCBCDecrypter(block, c.AESKey[16:])
-
-		for bytesRead := 0; bytesRead+15 < int(accountPkt.PasswordSize); bytesRead += 16 {
-			mode.CryptBlocks(decryptedBlock, accountPkt.Password[bytesRead:bytesRead+16])
-			decryptedPassword = append(decryptedPassword, decryptedBlock...)
-		}
-		decryptedPassword = utils.PKCS5Trimming(decryptedPassword)
-		password = utils.CToGoString(decryptedPassword)
+	player.Client = c
+	ip := c.GetIP()
+	player.IP = ip
+	if reason, banned := a.Bans.IsBanned(ip); banned {
+		a.Log.Info("Login rejected, IP is banned",
+			"function", "AuthHandler::HandleAccountLogin",
+			"accountName", player.AccountName,
+			"ip", ip,
+			"reason", reason)
+		a.audit(c, model.AuditEventLoginFailed, player, "ip banned")
+		a.sendLoginResult(c, packets.ResultIPBlocked, client.LoginFlagEulaAccepted, "")
+		return
+	}
+	player.MacStamp = macStampString(accountPkt.MacStamp)
+	if a.checkHardwareBan(player) {
+		a.audit(c, model.AuditEventLoginFailed, player, "hardware banned")
+		a.sendLoginResult(c, packets.ResultAccessDenied, client.LoginFlagAccountBlockWarning, "")
+		return
+	}
+	if result, locked := a.checkLockout(player.AccountName, ip); locked {
+		a.audit(c, model.AuditEventLoginFailed, player, "locked")
+		a.sendLoginResult(c, result, client.LoginFlagEulaAccepted, "")
+		return
 	}
 
-	account, found := a.DB.GetUserByNameAndPW(player.AccountName,
-		fmt.Sprintf("%s%s", a.Config.Database.DefaultSalt, password), a.Config)
-
+	account, found := a.verifyCredentials(c, player.AccountName, accountPkt)
 	if !found {
-		resultPkt := client.AuthClientResult{
-			RequestMessageID: 10010,
-			Result:           packets.ResultNotExist,
-			LoginFlag:        client.LoginFlagEulaAccepted,
-		}
 		a.Log.Debug("Failed login attempt",
 			"function", "AuthHandler::HandleAccountLogin",
-			"accountName", player.AccountName)
-		c.Send(resultPkt, client.AuthClientResultID)
+			"accountName", player.AccountName,
+			"ip", ip)
+		a.registerLoginFailure(player.AccountName, ip)
+		a.audit(c, model.AuditEventLoginFailed, player, "wrong credentials")
+		a.sendLoginResult(c, packets.ResultNotExist, client.LoginFlagEulaAccepted, "")
 		return
 	}
+	a.resetLoginFailures(player.AccountName)
 
-	player.Age = account.Age
+	player.Age = accountAge(account, time.Now())
 	player.AccountID = account.AccountID
 	player.IsBlocked = account.Blocked
 	player.LastServerIndex = account.LastLoginServerIdx
 	player.Permission = account.Permission
 
-	var resultPkt client.AuthClientResult
 	if player.IsBlocked {
-		resultPkt = client.AuthClientResult{
-			RequestMessageID: 10010,
-			Result:           packets.ResultAccessDenied,
-			LoginFlag:        client.LoginFlagAccountBlockWarning,
-		}
-		c.Send(resultPkt, client.AuthClientResultID)
+		a.audit(c, model.AuditEventLoginFailed, player, "blocked")
+		a.sendLoginResult(c, packets.ResultAccessDenied, client.LoginFlagAccountBlockWarning, "")
+		return
+	}
+
+	if a.Config.Account.RequireEmailVerification && account.EmailVerificationPending {
+		a.audit(c, model.AuditEventLoginFailed, player, "email not verified")
+		a.sendLoginResult(c, packets.ResultAccessDenied, client.LoginFlagEulaAccepted, a.Config.Account.UnverifiedMessage)
+		return
+	}
+
+	if a.missingEnrollment(account) {
+		a.Log.Warn("Login rejected, TOTP enrollment required",
+			"function", "AuthHandler::HandleAccountLogin",
+			"accountName", player.AccountName,
+			"permission", player.Permission)
+		a.audit(c, model.AuditEventLoginFailed, player, "totp not enrolled")
+		a.sendLoginResult(c, packets.ResultAccessDenied, client.LoginFlagEulaAccepted, "")
+		return
+	}
+
+	if ban, banned := a.DB.GetActiveAccountBan(player.AccountID); banned {
+		a.Log.Info("Login rejected, account is banned",
+			"function", "AuthHandler::HandleAccountLogin",
+			"accountName", player.AccountName,
+			"banID", ban.ID,
+			"endsAt", ban.EndsAt)
+		a.audit(c, model.AuditEventLoginFailed, player, "banned")
+		a.sendLoginResult(c, packets.ResultAccessDenied, client.LoginFlagAccountBlockWarning, a.banMessage(ban))
+		return
+	}
+
+	if message, active := a.maintenanceFor(player.Permission, 0, false); active {
+		a.audit(c, model.AuditEventLoginFailed, player, "maintenance")
+		a.sendLoginResult(c, packets.ResultAccessDenied, client.LoginFlagEulaAccepted, message)
+		return
+	}
+
+	loginFlag, eulaAccepted := a.eulaLoginFlag(player.AccountID)
+	if !eulaAccepted && a.Config.Eula.RequireAcceptance {
+		a.audit(c, model.AuditEventLoginFailed, player, "eula not accepted")
+		a.sendLoginResult(c, packets.ResultNeedAcceptEula, loginFlag, "")
+		return
+	}
+
+	if !a.resolveDuplicateLogin(player) {
+		a.audit(c, model.AuditEventLoginFailed, player, "duplicate login")
+		a.sendLoginResult(c, packets.ResultAlreadyExist, client.LoginFlagEulaAccepted, "")
 		return
 	}
 
 	c.IsAuthenticated = true
 	c.PlayerIdentifier = player.AccountName
 	a.Players.AddPlayer(player)
-	resultPkt = client.AuthClientResult{
-		RequestMessageID: 10010,
-		Result:           packets.ResultSuccess,
-		LoginFlag:        client.LoginFlagEulaAccepted,
+	a.recordMacStamp(player)
+	a.audit(c, model.AuditEventLoginSuccess, player, keyExchangeType(c))
+	a.sendLoginResult(c, packets.ResultSuccess, loginFlag, "")
+}
+
+// keyExchangeType names the cipher used for the password of the client.
+func keyExchangeType(c *net.Client) string {
+	if len(c.AESKey) == 0 {
+		return "des"
 	}
-	c.Send(resultPkt, client.AuthClientResultID)
+	return "aes"
+}
+
+// audit records an event of the client connection in the login audit trail, player may be nil.
+func (a *AuthHandler) audit(c *net.Client, event string, player *Player, detail string) {
+	entry := model.LoginAudits{
+		IP:            c.GetIP(),
+		Event:         event,
+		Detail:        detail,
+		ClientVersion: c.SupportedVersion,
+	}
+	if player != nil {
+		entry.AccountID = player.AccountID
+		entry.AccountName = player.AccountName
+		entry.ServerIdx = player.GameIndex
+	}
+	a.Audit.Record(entry)
+}
+
+// verifyCredentials checks the password or launcher token and, if enrolled, the second factor appended to it.
+func (a *AuthHandler) verifyCredentials(c *net.Client, accountName string,
+	accountPkt client.ClientAuthAccount) (*model.Accounts, bool) {
+	password := a.decryptPassword(c, accountPkt)
+	factor, valid := a.checkSecondFactor(accountName, password)
+	if !valid {
+		return nil, false
+	}
+
+	account, found := a.launcherLogin(accountName, factor.Password)
+	if !found {
+		account, found = a.Auth.Authenticate(accountName, factor.Password)
+	}
+	if !found || !a.consumeSecondFactor(account, factor) {
+		return nil, false
+	}
+	return account, true
+}
+
+// decryptPassword decrypts the password with DES, or with AES if the client exchanged a key before.
+func (a *AuthHandler) decryptPassword(c *net.Client, accountPkt client.ClientAuthAccount) string {
+	if len(c.AESKey) == 0 {
+		block, _ := des.NewCipher(a.DESKey[:])
+		var decryptedPassword []byte
+		encryptedBlock := make([]byte, 8)
+		for i := range 4 {
+			block.Decrypt(encryptedBlock, accountPkt.Password[i*8:(i+1)*8])
+			decryptedPassword = append(decryptedPassword, encryptedBlock...)
+		}
+		return utils.CToGoString(decryptedPassword)
+	}
+
+	size := min(int(accountPkt.PasswordSize), len(accountPkt.Password))
+	decryptedPassword, err := utils.DecryptAESCBC(c.AESKey, accountPkt.Password[:size-size%aes.BlockSize])
+	if err != nil {
+		a.Log.Error("Cannot decrypt AES password",
+			"function", "AuthHandler::decryptPassword",
+			"accountName", utils.CToGoString(accountPkt.Account),
+			"error", err.Error())
+		return ""
+	}
+	return utils.CToGoString(decryptedPassword)
+}
+
+// sendLoginResult answers the login request, the message is only sent to clients able to display it.
+func (a *AuthHandler) sendLoginResult(c *net.Client, result uint16, loginFlag int32, message string) {
+	a.sendResult(c, client.ClientAuthAccountID, result, loginFlag, message)
+}
+
+// supportsResultMessage checks if the client is able to display result messages.
+func supportsResultMessage(c *net.Client) bool {
+	return c.SupportedVersion >= client.AuthClientResultWithStringVersion
+}
+
+// sendResult answers a request with a result, the message is only sent to clients able to display it.
+func (a *AuthHandler) sendResult(c *net.Client, requestMessageID, result uint16, loginFlag int32, message string) {
+	if message == "" || !supportsResultMessage(c) {
+		resultPkt := client.AuthClientResult{
+			RequestMessageID: requestMessageID,
+			Result:           result,
+			LoginFlag:        loginFlag,
+		}
+		c.Send(resultPkt, client.AuthClientResultID)
+		return
+	}
+
+	messageBytes := append([]byte(message), 0)
+	resultPkt := client.AuthClientResultWithString{
+		RequestMessageID: requestMessageID,
+		Result:           result,
+		LoginFlag:        loginFlag,
+		MessageSize:      uint32(len(messageBytes)), //nolint:gosec // messages are short
+		Message:          messageBytes,
+	}
+	c.Send(resultPkt, client.AuthClientResultWithStringID)
+}
+
+// banMessage builds the message shown to a banned player from the configured templates.
+func (a *AuthHandler) banMessage(ban *model.AccountBans) string {
+	conf := a.Config.Security.AccountBan
+	if ban.EndsAt == nil {
+		return strings.NewReplacer("{reason}", ban.Reason).Replace(conf.PermanentMessage)
+	}
+	return strings.NewReplacer(
+		"{reason}", ban.Reason,
+		"{until}", ban.EndsAt.Format(conf.DateFormat),
+	).Replace(conf.Message)
 }
 
 func (a *AuthHandler) HandleServerList(c *net.Client) {
@@ -271,26 +512,13 @@ func (a *AuthHandler) HandleServerList(c *net.Client) {
 
 	player := a.Players.GetPlayer(c.PlayerIdentifier)
 
-	a.GameSrvs.mutex.Lock()
 	serverPkt := client.AuthClientServerList{
 		LastLoginServerIdx: player.LastServerIndex,
+		ServerInfo:         a.serverList(player),
 	}
-	if len(a.GameSrvs.Games) < 0xFFFF {
-		serverPkt.Servers = uint32(len(a.GameSrvs.Games)) //nolint:gosec // We have a check for overflow
+	if len(serverPkt.ServerInfo) < 0xFFFF {
+		serverPkt.Servers = uint32(len(serverPkt.ServerInfo)) //nolint:gosec // We have a check for overflow
 	}
-	for _, value := range a.GameSrvs.Games {
-		val := client.ServerInfo{
-			ServerIdx:     value.ServerIdx,
-			IsAdultServer: value.IsAdultServer,
-			ServerPort:    value.ServerPort,
-			UserRatio:     0,
-		}
-		copy(val.ServerIP[:], []byte(value.ServerIP))
-		copy(val.ServerScreenshotURL[:], []byte(value.ServerScreenshotURL))
-		copy(val.ServerName[:], []byte(value.ServerName))
-		serverPkt.ServerInfo = append(serverPkt.ServerInfo, val)
-	}
-	a.GameSrvs.mutex.Unlock()
 
 	c.Send(serverPkt, client.AuthClientServerListID)
 }
@@ -330,6 +558,7 @@ func (a *AuthHandler) HandlePublicKey(c *net.Client, pubKeyPkt client.ClientAuth
 		Key:  encryptedAES,
 	}
 	c.AESKey = aesKey
+	a.audit(c, model.AuditEventKeyExchange, nil, fmt.Sprintf("rsa %d", pubKeyPkt.Header.HeaderMessageId))
 	if pubKeyPkt.Header.HeaderMessageId == client.ClientAuthPublicKeyID1 {
 		c.Send(resultPkt, client.AuthClientAESKeyID1)
 	} else {
@@ -364,30 +593,107 @@ func (a *AuthHandler) HandleServerSelection(c *net.Client, serverSelectPkt clien
 		return
 	}
 
-	if (srv.IsAdultServer == 1) && (player.Age < a.Config.Server.AgeRestriction) {
+	display := a.Registry.Servers()[srv.ServerIdx]
+	if (display.Hidden || display.StaffOnly) && !a.isStaff(player) {
+		a.Log.Debug("Server selection rejected, server is hidden or staff-only",
+			"function", "AuthHandler::HandleServerSelection",
+			"serverIdx", serverSelectPkt.ServerIdx,
+			"accountName", player.AccountName)
+		c.Send(resultPkt, client.AuthClientSelectServerID)
+		return
+	}
+	displayed := display.displayed(*srv)
+
+	if message, active := a.maintenanceFor(player.Permission, srv.ServerIdx, true); active {
+		a.Log.Debug("Server selection rejected, server is under maintenance",
+			"function", "AuthHandler::HandleServerSelection",
+			"serverIdx", serverSelectPkt.ServerIdx,
+			"accountName", player.AccountName)
+		if supportsResultMessage(c) {
+			a.sendResult(c, client.ClientAuthSelectServerID, packets.ResultAccessDenied, 0, message)
+			return
+		}
+		c.Send(resultPkt, client.AuthClientSelectServerID)
+		return
+	}
+
+	if minAge := a.minimumAge(&displayed); player.Age < minAge {
 		resultPkt.Result = packets.ResultTooYoung
-		a.Log.Debug("Player too young to join adult server!",
+		a.Log.Debug("Player too young to join server!",
 			"function", "AuthHandler::HandleServerSelection",
 			"serverIdx", serverSelectPkt.ServerIdx,
-			"playerAge", player.Age)
+			"playerAge", player.Age,
+			"minAge", minAge)
+		a.audit(c, model.AuditEventServerSelect, player,
+			fmt.Sprintf("server %d rejected: too young", serverSelectPkt.ServerIdx))
 		c.Send(resultPkt, client.AuthClientSelectServerID)
 		return
 	}
 
+	if result, rejected := a.checkFatigue(player); rejected {
+		resultPkt.Result = result
+		a.audit(c, model.AuditEventServerSelect, player,
+			fmt.Sprintf("server %d rejected: play time limit", serverSelectPkt.ServerIdx))
+		c.Send(resultPkt, client.AuthClientSelectServerID)
+		return
+	}
+
+	// The slot is only taken with the reservation, another player may take it first.
+	for {
+		pendingTime, admitted := a.admitToServer(player, srv.ServerIdx)
+		if !admitted {
+			a.Log.Debug("Server is full, player queued",
+				"function", "AuthHandler::HandleServerSelection",
+				"serverIdx", serverSelectPkt.ServerIdx,
+				"accountName", player.AccountName,
+				"pendingTime", pendingTime)
+			if err := setOneTimeKey(c, &resultPkt, 0, pendingTime); err != nil {
+				a.Log.Error("Cannot encrypt pending time",
+					"function", "AuthHandler::HandleServerSelection",
+					"accountName", player.AccountName,
+					"error", err.Error())
+				c.Send(resultPkt, client.AuthClientSelectServerID)
+				return
+			}
+			resultPkt.Result = packets.ResultPending
+			c.Send(resultPkt, client.AuthClientSelectServerID)
+			return
+		}
+		if a.sendOneTimeKey(c, player, serverSelectPkt.ServerIdx) {
+			return
+		}
+	}
+}
+
+// sendOneTimeKey reserves the server for the player and sends the key to join it.
+// Returns false without answering if the server has no free slot anymore.
+func (a *AuthHandler) sendOneTimeKey(c *net.Client, player *Player, serverIdx uint32) bool {
+	resultPkt := client.AuthClientSelectServer{Result: packets.ResultAccessDenied}
 	otk, err := rand.Int(rand.Reader, big.NewInt(0x7FFFFFFFFFFFFFFF))
 	if err != nil {
 		a.Log.Error("Error generating randon one-time-key.",
-			"function", "AuthHandler::HandleServerSelection",
+			"function", "AuthHandler::sendOneTimeKey",
 			"error", err.Error())
+		c.Send(resultPkt, client.AuthClientSelectServerID)
+		return true
 	}
 
-	player.IsInGame = true
-	player.GameIndex = serverSelectPkt.ServerIdx
-	player.OneTimeKey = otk.Uint64()
+	if err = setOneTimeKey(c, &resultPkt, otk.Uint64(), 0); err != nil {
+		a.Log.Error("Cannot encrypt one-time key",
+			"function", "AuthHandler::sendOneTimeKey",
+			"accountName", player.AccountName,
+			"error", err.Error())
+		resultPkt = client.AuthClientSelectServer{Result: packets.ResultAccessDenied}
+		c.Send(resultPkt, client.AuthClientSelectServerID)
+		return true
+	}
+	if !a.issueOneTimeKey(player, serverIdx, otk.Uint64()) {
+		return false
+	}
 	resultPkt.Result = packets.ResultSuccess
-	resultPkt.OneTimeKey = player.OneTimeKey
-	resultPkt.PendingTime = 0
+	a.audit(c, model.AuditEventServerSelect, player, "success")
 	c.Send(resultPkt, client.AuthClientSelectServerID)
+	return true
 }
 
 func (a *AuthHandler) IsLoggedIn(c *net.Client, funcName string) bool {
@@ -406,5 +712,12 @@ func (a *AuthHandler) IsLoggedIn(c *net.Client, funcName string) bool {
 		c.Close()
 		return false
 	}
+	if player.Client != c {
+		a.Log.Info("Session was replaced by another login",
+			"function", fmt.Sprintf("AuthHandler::%s", funcName),
+			"accountName", c.PlayerIdentifier)
+		c.Close()
+		return false
+	}
 	return true
 }
@@ -0,0 +1,127 @@
+package entities
+
+import (
+	"errors"
+	"log/slog"
+	"mononoke-go/database"
+	"mononoke-go/model"
+	"mononoke-go/net/packets/game"
+	"mononoke-go/utils"
+	"time"
+)
+
+var (
+	ErrNoPremium          = errors.New("account has no active premium")
+	ErrEventCodeNotFound  = errors.New("event code not found")
+	ErrInvalidEventPeriod = errors.New("event code ends before it starts")
+)
+
+// PremiumService manages premium entitlements and event codes for the admin API and the CLI.
+type PremiumService struct {
+	DB       *database.GormDatabase
+	Accounts *AccountService
+	Log      *slog.Logger
+}
+
+// Grant gives the account premium for the duration, a duration of 0 grants it permanently.
+func (s *PremiumService) Grant(name string, duration time.Duration, reason string) (*model.PremiumEntitlements, error) {
+	account, err := s.Accounts.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	entitlement := &model.PremiumEntitlements{AccountID: account.AccountID, StartsAt: now, Reason: reason}
+	if duration > 0 {
+		endsAt := now.Add(duration)
+		entitlement.EndsAt = &endsAt
+	}
+	if err = s.DB.AddPremium(entitlement); err != nil {
+		return nil, err
+	}
+	s.Log.Info("Premium granted",
+		"function", "PremiumService::Grant",
+		"accountName", account.AccountName,
+		"endsAt", entitlement.EndsAt,
+		"reason", reason)
+	return entitlement, nil
+}
+
+func (s *PremiumService) Revoke(name string) error {
+	account, err := s.Accounts.Get(name)
+	if err != nil {
+		return err
+	}
+	revoked, err := s.DB.RevokePremium(account.AccountID)
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return ErrNoPremium
+	}
+	s.Log.Info("Premium revoked",
+		"function", "PremiumService::Revoke",
+		"accountName", account.AccountName)
+	return nil
+}
+
+// AddEventCode assigns an event code to the account, or to all accounts if name is empty.
+func (s *PremiumService) AddEventCode(name string, code uint32, campaign string,
+	startsAt time.Time, endsAt *time.Time) (*model.EventCodes, error) {
+	if endsAt != nil && !endsAt.After(startsAt) {
+		return nil, ErrInvalidEventPeriod
+	}
+	eventCode := &model.EventCodes{Code: code, Campaign: campaign, StartsAt: startsAt, EndsAt: endsAt}
+	if name != "" {
+		account, err := s.Accounts.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		eventCode.AccountID = account.AccountID
+	}
+	if err := s.DB.AddEventCode(eventCode); err != nil {
+		return nil, err
+	}
+	s.Log.Info("Event code added",
+		"function", "PremiumService::AddEventCode",
+		"id", eventCode.ID,
+		"accountID", eventCode.AccountID,
+		"code", code,
+		"campaign", campaign)
+	return eventCode, nil
+}
+
+func (s *PremiumService) RemoveEventCode(id uint32) error {
+	deleted, err := s.DB.DeleteEventCode(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrEventCodeNotFound
+	}
+	return nil
+}
+
+func (s *PremiumService) EventCodes() ([]model.EventCodes, error) {
+	return s.DB.GetActiveEventCodes()
+}
+
+// pcBangStatus reports premium accounts, and players connecting from a configured PC-bang network.
+func (a *GameHandler) pcBangStatus(player *Player) uint8 {
+	if a.DB.HasActivePremium(player.AccountID) {
+		return game.PCBangUserPremium
+	}
+	for _, network := range a.Config.PCBang.Networks {
+		prefix, err := utils.ParseIPOrCIDR(network)
+		if err != nil {
+			a.Log.Warn("Invalid PC-bang network",
+				"function", "GameHandler::pcBangStatus",
+				"network", network,
+				"error", err.Error())
+			continue
+		}
+		if utils.PrefixContainsIP(prefix, player.IP) {
+			return game.PCBangUserPCBang
+		}
+	}
+	return game.PCBangUserNone
+}
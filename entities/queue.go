@@ -0,0 +1,151 @@
+package entities
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+type queueEntry struct {
+	AccountName string
+	RefreshedAt time.Time
+}
+
+type serverQueue struct {
+	Priority []queueEntry
+	Normal   []queueEntry
+}
+
+// LoginQueue holds the players waiting for a free slot on a full server, staff is queued in a priority lane.
+// Players have to repeat their server selection within the timeout to keep their place.
+type LoginQueue struct {
+	Timeout time.Duration
+	queues  map[uint32]*serverQueue
+	mutex   sync.Mutex
+}
+
+func NewLoginQueue(timeout time.Duration) *LoginQueue {
+	return &LoginQueue{Timeout: timeout, queues: make(map[uint32]*serverQueue)}
+}
+
+// Enqueue adds the player to the queue of the server if not already queued and returns its position.
+// A player can only wait for one server at a time.
+func (lq *LoginQueue) Enqueue(serverIdx uint32, accountName string, priority bool) int {
+	lq.mutex.Lock()
+	defer lq.mutex.Unlock()
+
+	for idx, queue := range lq.queues {
+		if idx != serverIdx {
+			queue.remove(accountName)
+		}
+	}
+
+	queue, exists := lq.queues[serverIdx]
+	if !exists {
+		queue = new(serverQueue)
+		lq.queues[serverIdx] = queue
+	}
+
+	now := time.Now()
+	lq.expire(queue, now)
+	if position := queue.refresh(accountName, now); position >= 0 {
+		return position
+	}
+	entry := queueEntry{AccountName: accountName, RefreshedAt: now}
+	if priority {
+		queue.Priority = append(queue.Priority, entry)
+	} else {
+		queue.Normal = append(queue.Normal, entry)
+	}
+	return queue.position(accountName)
+}
+
+// Next removes the first player waiting for the server from the queue and reports its lane.
+func (lq *LoginQueue) Next(serverIdx uint32) (string, bool, bool) {
+	lq.mutex.Lock()
+	defer lq.mutex.Unlock()
+
+	queue, exists := lq.queues[serverIdx]
+	if !exists {
+		return "", false, false
+	}
+	lq.expire(queue, time.Now())
+	switch {
+	case len(queue.Priority) > 0:
+		next := queue.Priority[0]
+		queue.Priority = queue.Priority[1:]
+		return next.AccountName, true, true
+	case len(queue.Normal) > 0:
+		next := queue.Normal[0]
+		queue.Normal = queue.Normal[1:]
+		return next.AccountName, false, true
+	}
+	return "", false, false
+}
+
+// Requeue puts a player taken with Next back to the head of its lane.
+func (lq *LoginQueue) Requeue(serverIdx uint32, accountName string, priority bool) {
+	lq.mutex.Lock()
+	defer lq.mutex.Unlock()
+
+	queue, exists := lq.queues[serverIdx]
+	if !exists {
+		queue = new(serverQueue)
+		lq.queues[serverIdx] = queue
+	}
+	queue.remove(accountName)
+	entry := queueEntry{AccountName: accountName, RefreshedAt: time.Now()}
+	if priority {
+		queue.Priority = slices.Insert(queue.Priority, 0, entry)
+	} else {
+		queue.Normal = slices.Insert(queue.Normal, 0, entry)
+	}
+}
+
+// Remove removes the player from every queue.
+func (lq *LoginQueue) Remove(accountName string) {
+	lq.mutex.Lock()
+	defer lq.mutex.Unlock()
+	for _, queue := range lq.queues {
+		queue.remove(accountName)
+	}
+}
+
+// expire drops the players which didn't repeat their server selection within the timeout.
+func (lq *LoginQueue) expire(queue *serverQueue, now time.Time) {
+	if lq.Timeout == 0 {
+		return
+	}
+	stale := func(entry queueEntry) bool { return now.Sub(entry.RefreshedAt) > lq.Timeout }
+	queue.Priority = slices.DeleteFunc(queue.Priority, stale)
+	queue.Normal = slices.DeleteFunc(queue.Normal, stale)
+}
+
+func (q *serverQueue) refresh(accountName string, now time.Time) int {
+	position := q.position(accountName)
+	switch {
+	case position < 0:
+	case position < len(q.Priority):
+		q.Priority[position].RefreshedAt = now
+	default:
+		q.Normal[position-len(q.Priority)].RefreshedAt = now
+	}
+	return position
+}
+
+func (q *serverQueue) position(accountName string) int {
+	byName := func(entry queueEntry) bool { return entry.AccountName == accountName }
+	if position := slices.IndexFunc(q.Priority, byName); position >= 0 {
+		return position
+	}
+	if position := slices.IndexFunc(q.Normal, byName); position >= 0 {
+		return len(q.Priority) + position
+	}
+	return -1
+}
+
+func (q *serverQueue) remove(accountName string) {
+	byName := func(entry queueEntry) bool { return entry.AccountName == accountName }
+	q.Priority = slices.DeleteFunc(q.Priority, byName)
+	q.Normal = slices.DeleteFunc(q.Normal, byName)
+}
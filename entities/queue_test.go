@@ -0,0 +1,212 @@
+package entities_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"mononoke-go/config"
+	"mononoke-go/entities"
+	"mononoke-go/net/packets"
+	"mononoke-go/net/packets/client"
+	"mononoke-go/utils"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoginQueuePriorityLane(t *testing.T) {
+	queue := entities.NewLoginQueue(0)
+	if position := queue.Enqueue(1, "alice", false); position != 0 {
+		t.Errorf("alice queued at %d, want 0", position)
+	}
+	if position := queue.Enqueue(1, "bob", false); position != 1 {
+		t.Errorf("bob queued at %d, want 1", position)
+	}
+	if position := queue.Enqueue(1, "staff", true); position != 0 {
+		t.Errorf("staff queued at %d, want 0", position)
+	}
+	if position := queue.Enqueue(1, "bob", false); position != 2 {
+		t.Errorf("bob moved to %d when selecting the server again, want 2", position)
+	}
+
+	for _, want := range []string{"staff", "alice", "bob"} {
+		if next, priority, queued := queue.Next(1); !queued || next != want || priority != (want == "staff") {
+			t.Errorf("Next() = %q, %t, %t, want %q", next, priority, queued, want)
+		}
+	}
+	if next, _, queued := queue.Next(1); queued {
+		t.Errorf("Next() of empty queue = %q", next)
+	}
+}
+
+func TestLoginQueueRequeue(t *testing.T) {
+	queue := entities.NewLoginQueue(0)
+	queue.Enqueue(1, "alice", false)
+	queue.Enqueue(1, "bob", false)
+
+	next, priority, _ := queue.Next(1)
+	queue.Requeue(1, next, priority)
+	if next, _, _ = queue.Next(1); next != "alice" {
+		t.Errorf("Next() = %q after requeueing alice, want alice", next)
+	}
+}
+
+func TestLoginQueueOneServerPerPlayer(t *testing.T) {
+	queue := entities.NewLoginQueue(0)
+	queue.Enqueue(1, "alice", false)
+	queue.Enqueue(2, "alice", false)
+
+	if next, _, queued := queue.Next(1); queued {
+		t.Errorf("%q still queued for the server selected before", next)
+	}
+	if next, _, queued := queue.Next(2); !queued || next != "alice" {
+		t.Errorf("Next(2) = %q, %t, want alice", next, queued)
+	}
+}
+
+func TestLoginQueueExpiry(t *testing.T) {
+	queue := entities.NewLoginQueue(50 * time.Millisecond)
+	queue.Enqueue(1, "alice", false)
+	queue.Enqueue(1, "bob", false)
+	time.Sleep(30 * time.Millisecond)
+	queue.Enqueue(1, "bob", false)
+	time.Sleep(30 * time.Millisecond)
+
+	if position := queue.Enqueue(1, "carol", false); position != 1 {
+		t.Errorf("carol queued at %d, want 1 behind bob", position)
+	}
+	if next, _, _ := queue.Next(1); next != "bob" {
+		t.Errorf("Next() = %q, want bob, alice did not refresh her place", next)
+	}
+}
+
+func TestAdmitToServer(t *testing.T) {
+	conf := new(config.Configuration)
+	conf.Queue.DefaultCapacity = 1
+	conf.Queue.SecondsPerPlayer = 30
+	conf.Queue.PriorityPermission = 100
+	handler := newTestAuthHandler(conf)
+
+	alice := addTestPlayer(handler, "alice", 0, false)
+	if _, admitted := handler.AdmitToServer(alice, 1); !admitted {
+		t.Fatal("alice not admitted to the empty server")
+	}
+	alice.GameIndex, alice.IsInGame = 1, true
+
+	bob := addTestPlayer(handler, "bob", 0, false)
+	if pendingTime, admitted := handler.AdmitToServer(bob, 1); admitted || pendingTime != 30 {
+		t.Errorf("AdmitToServer(bob) = %d, %t, want 30 seconds pending", pendingTime, admitted)
+	}
+	carol := addTestPlayer(handler, "carol", 0, false)
+	if pendingTime, admitted := handler.AdmitToServer(carol, 1); admitted || pendingTime != 60 {
+		t.Errorf("AdmitToServer(carol) = %d, %t, want 60 seconds pending", pendingTime, admitted)
+	}
+	staff := addTestPlayer(handler, "staff", 0, false)
+	staff.Permission = 100
+	if pendingTime, admitted := handler.AdmitToServer(staff, 1); admitted || pendingTime != 30 {
+		t.Errorf("AdmitToServer(staff) = %d, %t, want 30 seconds pending ahead of the others", pendingTime, admitted)
+	}
+	if _, admitted := handler.AdmitToServer(bob, 2); !admitted {
+		t.Error("bob not admitted to a server without capacity limit")
+	}
+}
+
+func TestAdmitQueuedSendsOneTimeKey(t *testing.T) {
+	conf := new(config.Configuration)
+	conf.Queue.DefaultCapacity = 1
+	conf.Queue.PriorityPermission = 100
+	handler := newTestAuthHandler(conf)
+	gameClient, _ := newTestClient(t)
+	handler.GameSrvs.AddGame(&entities.Game{Client: gameClient, ServerIdx: 1})
+
+	alice := addTestPlayer(handler, "alice", 1, true)
+	gone := addTestPlayer(handler, "gone", 0, false)
+	bob := addTestPlayer(handler, "bob", 0, false)
+	bobClient, bobConn := newTestClient(t)
+	bob.Client = bobClient
+	for _, player := range []*entities.Player{gone, bob} {
+		if _, admitted := handler.AdmitToServer(player, 1); admitted {
+			t.Fatalf("%s admitted to the full server", player.AccountName)
+		}
+	}
+	gone.AuthDisconnected = true
+
+	handler.Players.RemovePlayer(alice)
+	handler.AdmitQueued(1)
+
+	packetID, packet, err := readPacket(bobConn)
+	if err != nil || packetID != client.AuthClientSelectServerID {
+		t.Fatalf("bob received packet %d (%v), want %d", packetID, err, client.AuthClientSelectServerID)
+	}
+	resultPkt := client.AuthClientSelectServer{}
+	if err = utils.Unmarshal(bytes.NewBuffer(packet), binary.LittleEndian, &resultPkt, 0); err != nil {
+		t.Fatal(err.Error())
+	}
+	if resultPkt.Result != packets.ResultSuccess || resultPkt.OneTimeKey == 0 {
+		t.Errorf("bob received result %d with key %#x, want a one-time key", resultPkt.Result, resultPkt.OneTimeKey)
+	}
+	if !bob.IsInGame || bob.GameIndex != 1 || bob.OneTimeKey != resultPkt.OneTimeKey {
+		t.Errorf("bob holds no reservation for server 1 with the sent key")
+	}
+	if next, _, queued := handler.Queue.Next(1); queued {
+		t.Errorf("%q still queued", next)
+	}
+}
+
+func TestReserveServerCapacity(t *testing.T) {
+	handler := newTestAuthHandler(new(config.Configuration))
+	alice := addTestPlayer(handler, "alice", 0, false)
+	bob := addTestPlayer(handler, "bob", 0, false)
+
+	if !handler.Players.ReserveServer(alice, 1, 1, 1, time.Time{}) {
+		t.Fatal("reservation of the free slot failed")
+	}
+	if handler.Players.ReserveServer(bob, 1, 1, 2, time.Time{}) {
+		t.Error("reservation beyond the capacity accepted")
+	}
+	if bob.IsInGame || bob.OneTimeKey != 0 {
+		t.Error("rejected reservation changed the player")
+	}
+	if !handler.Players.ReserveServer(bob, 1, 0, 2, time.Time{}) {
+		t.Error("reservation on a server without capacity limit failed")
+	}
+}
+
+func TestAdmitQueuedRespectsCapacity(t *testing.T) {
+	conf := new(config.Configuration)
+	conf.Queue.DefaultCapacity = 5
+	conf.Queue.PriorityPermission = 100
+	handler := newTestAuthHandler(conf)
+	gameClient, _ := newTestClient(t)
+	handler.GameSrvs.AddGame(&entities.Game{Client: gameClient, ServerIdx: 1})
+
+	for idx := range 5 {
+		addTestPlayer(handler, fmt.Sprintf("playing%d", idx), 1, true)
+	}
+	for idx := range 10 {
+		player := addTestPlayer(handler, fmt.Sprintf("queued%d", idx), 0, false)
+		player.Client, _ = newTestClient(t)
+		if _, admitted := handler.AdmitToServer(player, 1); admitted {
+			t.Fatalf("%s admitted to the full server", player.AccountName)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for idx := range 5 {
+		handler.Players.RemovePlayer(handler.Players.GetPlayer(fmt.Sprintf("playing%d", idx)))
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			handler.AdmitQueued(1)
+		}()
+		go func() {
+			defer wg.Done()
+			handler.AdmitQueued(1)
+		}()
+	}
+	wg.Wait()
+
+	if inGame := handler.Players.CountInGame(1); inGame != 5 {
+		t.Errorf("%d players in game or about to join, want the capacity of 5", inGame)
+	}
+}
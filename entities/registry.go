@@ -0,0 +1,177 @@
+package entities
+
+import (
+	"cmp"
+	"log/slog"
+	"mononoke-go/config"
+	"mononoke-go/database"
+	"mononoke-go/net/packets/client"
+	"slices"
+	"sync"
+)
+
+// serverDisplay holds the server list settings of a game server from the configuration and the database.
+type serverDisplay struct {
+	Name          string
+	IP            string
+	Port          int32
+	ScreenshotURL string
+	AdultServer   *bool
+	DisplayOrder  int32
+	Hidden        bool
+	StaffOnly     bool
+}
+
+// ServerRegistry holds the display settings of the configured servers and the database entries.
+type ServerRegistry struct {
+	DB      *database.GormDatabase
+	Config  *config.Configuration
+	Log     *slog.Logger
+	servers map[uint32]serverDisplay
+	mutex   sync.RWMutex
+}
+
+// Reload reads the database entries again, they replace the configured settings of the same server.
+func (sr *ServerRegistry) Reload() error {
+	servers, err := sr.DB.GetGameServers()
+	if err != nil {
+		return err
+	}
+
+	registry := make(map[uint32]serverDisplay, len(sr.Config.GameServers)+len(servers))
+	for _, server := range sr.Config.GameServers {
+		registry[server.ServerIdx] = configDisplay(server)
+	}
+	for _, server := range servers {
+		registry[server.ServerIdx] = serverDisplay{
+			Name:          server.Name,
+			IP:            server.IP,
+			Port:          server.Port,
+			ScreenshotURL: server.ScreenshotURL,
+			AdultServer:   server.AdultServer,
+			DisplayOrder:  server.DisplayOrder,
+			Hidden:        server.Hidden,
+			StaffOnly:     server.StaffOnly,
+		}
+	}
+
+	sr.mutex.Lock()
+	sr.servers = registry
+	sr.mutex.Unlock()
+	sr.Log.Debug("Server registry reloaded",
+		"function", "ServerRegistry::Reload",
+		"entries", len(registry))
+	return nil
+}
+
+// Servers returns the display settings of all registered servers, the map must not be modified.
+func (sr *ServerRegistry) Servers() map[uint32]serverDisplay {
+	sr.mutex.RLock()
+	defer sr.mutex.RUnlock()
+	return sr.servers
+}
+
+// listable checks if an offline server has the settings needed to show it in the server list.
+func (d serverDisplay) listable() bool {
+	return d.Name != "" && d.IP != "" && d.Port != 0
+}
+
+func configDisplay(server config.GameServer) serverDisplay {
+	return serverDisplay{
+		Name:          server.Name,
+		IP:            server.IP,
+		Port:          server.Port,
+		ScreenshotURL: server.ScreenshotURL,
+		AdultServer:   server.AdultServer,
+		DisplayOrder:  server.DisplayOrder,
+		Hidden:        server.Hidden,
+		StaffOnly:     server.StaffOnly,
+	}
+}
+
+// displayed returns the game server with the overrides of the registry applied.
+func (d serverDisplay) displayed(srv Game) Game {
+	if d.Name != "" {
+		srv.ServerName = d.Name
+	}
+	if d.IP != "" {
+		srv.ServerIP = d.IP
+	}
+	if d.Port != 0 {
+		srv.ServerPort = d.Port
+	}
+	if d.ScreenshotURL != "" {
+		srv.ServerScreenshotURL = d.ScreenshotURL
+	}
+	if d.AdultServer != nil {
+		srv.IsAdultServer = 0
+		if *d.AdultServer {
+			srv.IsAdultServer = 1
+		}
+	}
+	return srv
+}
+
+// isStaff checks if the player may see and join staff-only servers.
+func (a *AuthHandler) isStaff(player *Player) bool {
+	return player.Permission >= a.Config.ServerList.StaffPermission
+}
+
+// listedServer is a server list entry together with its position.
+type listedServer struct {
+	DisplayOrder int32
+	Info         client.ServerInfo
+}
+
+// serverList builds the sorted server list for the player. Servers of the registry which are not connected
+// are listed as offline if they have a name and an address.
+func (a *AuthHandler) serverList(player *Player) []client.ServerInfo {
+	registry := a.Registry.Servers()
+	staff := a.isStaff(player)
+	var listed []listedServer
+
+	add := func(srv *Game, display serverDisplay, userRatio uint16) {
+		if display.Hidden || (display.StaffOnly && !staff) {
+			return
+		}
+		if a.Config.Server.HideAgeLimited && player.Age < a.minimumAge(srv) {
+			return
+		}
+		info := client.ServerInfo{
+			ServerIdx:     srv.ServerIdx,
+			IsAdultServer: srv.IsAdultServer,
+			ServerPort:    srv.ServerPort,
+			UserRatio:     userRatio,
+		}
+		copy(info.ServerIP[:], []byte(srv.ServerIP))
+		copy(info.ServerScreenshotURL[:], []byte(srv.ServerScreenshotURL))
+		copy(info.ServerName[:], []byte(srv.ServerName))
+		listed = append(listed, listedServer{DisplayOrder: display.DisplayOrder, Info: info})
+	}
+
+	a.GameSrvs.mutex.Lock()
+	for serverIdx, value := range a.GameSrvs.Games {
+		display := registry[serverIdx]
+		srv := display.displayed(*value)
+		add(&srv, display, a.userRatio(value))
+	}
+	if a.Config.ServerList.ShowOffline {
+		for serverIdx, display := range registry {
+			if _, online := a.GameSrvs.Games[serverIdx]; !online && display.listable() {
+				srv := display.displayed(Game{ServerIdx: serverIdx})
+				add(&srv, display, a.Config.ServerList.OfflineUserRatio)
+			}
+		}
+	}
+	a.GameSrvs.mutex.Unlock()
+
+	slices.SortFunc(listed, func(first, second listedServer) int {
+		return cmp.Or(cmp.Compare(first.DisplayOrder, second.DisplayOrder),
+			cmp.Compare(first.Info.ServerIdx, second.Info.ServerIdx))
+	})
+	servers := make([]client.ServerInfo, 0, len(listed))
+	for _, entry := range listed {
+		servers = append(servers, entry.Info)
+	}
+	return servers
+}
@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"fmt"
+	"mononoke-go/net/packets"
+	"mononoke-go/utils"
+	"time"
+)
+
+// verifySecurityCode checks the secondary password of a player and locks it after too many failures.
+// Accounts without a secondary password always pass.
+func (a *GameHandler) verifySecurityCode(player *Player, securityCode string) uint32 {
+	account, found := a.DB.GetUserByID(player.AccountID)
+	if !found {
+		a.Log.Error("Account for security code check not found",
+			"function", "GameHandler::verifySecurityCode",
+			"accountID", player.AccountID,
+			"accountName", player.AccountName)
+		return packets.ResultNotExist
+	}
+
+	if account.SecurityCode == "" {
+		return packets.ResultSuccess
+	}
+
+	now := time.Now()
+	if account.SecurityCodeLockedUntil != nil && account.SecurityCodeLockedUntil.After(now) {
+		a.Log.Info("Security code is locked",
+			"function", "GameHandler::verifySecurityCode",
+			"accountName", player.AccountName,
+			"lockedUntil", *account.SecurityCodeLockedUntil)
+		return packets.ResultAccessDenied
+	}
+
+	if utils.VerifyPassword(fmt.Sprintf("%s%s", a.Config.Database.DefaultSalt, securityCode), account.SecurityCode) {
+		if account.SecurityCodeFailures > 0 {
+			a.saveSecurityCodeFailures(player, 0, nil)
+		}
+		return packets.ResultSuccess
+	}
+
+	conf := a.Config.Security.SecurityCode
+	failures := account.SecurityCodeFailures + 1
+	var lockedUntil *time.Time
+	if conf.MaxFailures > 0 && failures >= conf.MaxFailures {
+		until := now.Add(time.Duration(conf.LockSeconds) * time.Second)
+		lockedUntil = &until
+		failures = 0
+		a.Log.Warn("Too many wrong security codes, locking",
+			"function", "GameHandler::verifySecurityCode",
+			"accountName", player.AccountName,
+			"lockedUntil", until)
+	}
+	a.saveSecurityCodeFailures(player, failures, lockedUntil)
+	return packets.ResultPasswordMismatch
+}
+
+func (a *GameHandler) saveSecurityCodeFailures(player *Player, failures uint32, lockedUntil *time.Time) {
+	if err := a.DB.UpdateSecurityCodeFailures(player.AccountID, failures, lockedUntil); err != nil {
+		a.Log.Error("Cannot update security code failures",
+			"function", "GameHandler::saveSecurityCodeFailures",
+			"accountName", player.AccountName,
+			"error", err.Error())
+	}
+}
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"mononoke-go/model"
+	"mononoke-go/utils"
+	"time"
+)
+
+const totpSkew = 1
+
+// secondFactor is the result of stripping a TOTP or recovery code from the end of a password.
+type secondFactor struct {
+	Password     string
+	TOTPStep     int64
+	RecoveryHash string
+}
+
+// checkSecondFactor splits the password of accounts with TOTP enabled into password and code.
+// The code has to be either the current TOTP code or an unused recovery code.
+func (a *AuthHandler) checkSecondFactor(accountName, password string) (secondFactor, bool) {
+	account, found := a.DB.GetUserByName(accountName)
+	if !found || !account.TOTPEnabled {
+		return secondFactor{Password: password}, true
+	}
+
+	if len(password) > utils.TOTPDigits {
+		split := len(password) - utils.TOTPDigits
+		step, valid := utils.VerifyTOTP(account.TOTPSecret, password[split:], time.Now(), totpSkew)
+		if valid && step > account.TOTPLastStep {
+			return secondFactor{Password: password[:split], TOTPStep: step}, true
+		}
+	}
+
+	if len(password) > utils.RecoveryCodeLength {
+		split := len(password) - utils.RecoveryCodeLength
+		hash := utils.HashRecoveryCode(password[split:])
+		if a.DB.HasRecoveryCode(account.AccountID, hash) {
+			return secondFactor{Password: password[:split], RecoveryHash: hash}, true
+		}
+	}
+
+	a.Log.Info("Missing or invalid second factor",
+		"function", "AuthHandler::checkSecondFactor",
+		"accountName", accountName)
+	return secondFactor{}, false
+}
+
+// consumeSecondFactor invalidates the used code once the password was verified.
+func (a *AuthHandler) consumeSecondFactor(account *model.Accounts, factor secondFactor) bool {
+	if factor.RecoveryHash != "" {
+		if !a.DB.UseRecoveryCode(account.AccountID, factor.RecoveryHash) {
+			return false
+		}
+		a.Log.Info("Recovery code used",
+			"function", "AuthHandler::consumeSecondFactor",
+			"accountName", account.AccountName)
+		return true
+	}
+
+	if factor.TOTPStep > 0 {
+		if err := a.DB.UpdateTOTPLastStep(account.AccountID, factor.TOTPStep); err != nil {
+			a.Log.Error("Cannot update last TOTP step",
+				"function", "AuthHandler::consumeSecondFactor",
+				"accountName", account.AccountName,
+				"error", err.Error())
+		}
+	}
+	return true
+}
+
+// missingEnrollment checks if the account has to use TOTP but is not enrolled.
+func (a *AuthHandler) missingEnrollment(account *model.Accounts) bool {
+	enforce := a.Config.Security.TOTP.EnforcePermission
+	return enforce > 0 && account.Permission >= enforce && !account.TOTPEnabled
+}
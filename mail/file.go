@@ -0,0 +1,22 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileSender writes every mail as .eml file into a directory, meant for testing.
+type FileSender struct {
+	Directory string
+	From      string
+}
+
+func (s *FileSender) Send(to, subject, body string) error {
+	if err := os.MkdirAll(s.Directory, 0o750); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), filepath.Base(to))
+	return os.WriteFile(filepath.Join(s.Directory, name), message(s.From, to, subject, body), 0o600)
+}
@@ -0,0 +1,54 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"mononoke-go/config"
+	netmail "net/mail"
+)
+
+const (
+	SenderNone = "none"
+	SenderLog  = "log"
+	SenderFile = "file"
+	SenderSMTP = "smtp"
+)
+
+var ErrUnknownSender = errors.New("unknown mail sender")
+
+// Sender delivers mails to accounts.
+type Sender interface {
+	Send(to, subject, body string) error
+}
+
+// New creates the sender selected in the configuration.
+func New(conf *config.Configuration, log *slog.Logger) (Sender, error) {
+	switch conf.Mail.Sender {
+	case SenderNone:
+		return &NoneSender{}, nil
+	case SenderLog:
+		return &LogSender{Log: log}, nil
+	case SenderFile:
+		return &FileSender{Directory: conf.Mail.Directory, From: conf.Mail.From}, nil
+	case SenderSMTP:
+		if _, err := netmail.ParseAddress(conf.Mail.From); err != nil {
+			return nil, fmt.Errorf("invalid mail.from %q: %w", conf.Mail.From, err)
+		}
+		return &SMTPSender{
+			Host:     conf.Mail.SMTP.Host,
+			Port:     conf.Mail.SMTP.Port,
+			Username: conf.Mail.SMTP.Username,
+			Password: conf.Mail.SMTP.Password,
+			From:     conf.Mail.From,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSender, conf.Mail.Sender)
+	}
+}
+
+// message formats a plain text mail.
+func message(from, to, subject, body string) []byte {
+	return fmt.Appendf(nil, "From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n", from, to, subject, body)
+}
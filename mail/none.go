@@ -0,0 +1,12 @@
+package mail
+
+import "errors"
+
+var ErrMailDisabled = errors.New("sending mails is disabled, configure mail.sender")
+
+// NoneSender rejects every mail, it is used until a sender is configured.
+type NoneSender struct{}
+
+func (s *NoneSender) Send(_, _, _ string) error {
+	return ErrMailDisabled
+}
@@ -0,0 +1,31 @@
+package mail
+
+import (
+	"fmt"
+	netmail "net/mail"
+	"net/smtp"
+)
+
+// SMTPSender delivers mails through an SMTP server, STARTTLS is used if the server supports it.
+type SMTPSender struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTPSender) Send(to, subject, body string) error {
+	// From may contain a display name, the envelope only takes the address.
+	from, err := netmail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address %q: %w", s.From, err)
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	addr := fmt.Sprintf("%s:%d", s.Host, s.Port)
+	return smtp.SendMail(addr, auth, from.Address, []string{to}, message(s.From, to, subject, body))
+}
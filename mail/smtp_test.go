@@ -0,0 +1,99 @@
+package mail_test
+
+import (
+	"bufio"
+	"mononoke-go/mail"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeSMTP accepts one mail and returns the received commands and the message.
+func fakeSMTP(t *testing.T) (string, int, <-chan []string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var lines []string
+		defer func() { received <- lines }()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+			switch {
+			case inData && line == ".":
+				inData = false
+				reply("250 OK")
+			case inData:
+			case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(line, "MAIL FROM:"), strings.HasPrefix(line, "RCPT TO:"):
+				reply("250 OK")
+			case line == "DATA":
+				inData = true
+				reply("354 Go ahead")
+			case line == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Not implemented")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, received
+}
+
+func TestSMTPSenderWithDisplayName(t *testing.T) {
+	host, port, received := fakeSMTP(t)
+	sender := &mail.SMTPSender{Host: host, Port: port, From: "mononoke-go <noreply@localhost>"}
+
+	if err := sender.Send("alice@example.com", "Verify", "token"); err != nil {
+		t.Fatal(err.Error())
+	}
+	lines := <-received
+	if !contains(lines, "MAIL FROM:<noreply@localhost>") {
+		t.Errorf("envelope sender not the plain address, received %q", lines)
+	}
+	if !contains(lines, "RCPT TO:<alice@example.com>") {
+		t.Errorf("recipient missing, received %q", lines)
+	}
+	if !contains(lines, "From: mononoke-go <noreply@localhost>") {
+		t.Errorf("From header without display name, received %q", lines)
+	}
+}
+
+func TestSMTPSenderInvalidFrom(t *testing.T) {
+	sender := &mail.SMTPSender{Host: "127.0.0.1", Port: 1, From: "mononoke-go <noreply"}
+	if err := sender.Send("alice@example.com", "Verify", "token"); err == nil {
+		t.Error("mail sent with an invalid sender address")
+	}
+}
+
+func contains(lines []string, want string) bool {
+	for _, line := range lines {
+		if strings.HasPrefix(line, want) {
+			return true
+		}
+	}
+	return false
+}
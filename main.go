@@ -3,9 +3,12 @@ package main
 
 import (
 	"fmt"
+	"mononoke-go/cli"
 	"mononoke-go/config"
 	"mononoke-go/database"
 	"mononoke-go/engine"
+	"mononoke-go/entities"
+	"mononoke-go/mail"
 	"mononoke-go/utils"
 	"os"
 )
@@ -42,6 +45,36 @@ func main() {
 		panic(err)
 	}
 	defer db.Close()
+
+	if len(os.Args) > 1 {
+		mailSender, mailErr := mail.New(conf, logger)
+		if mailErr != nil {
+			logger.Error("Cannot create mail sender!",
+				"function", "main::main",
+				"error", mailErr.Error())
+			db.Close()
+			os.Exit(1)
+		}
+		accountService := &entities.AccountService{DB: db, Config: conf, Mail: mailSender, Log: logger}
+		admin := cli.CLI{
+			DB:       db,
+			Config:   conf,
+			Accounts: accountService,
+			Hardware: &entities.HardwareService{DB: db, Log: logger},
+			Premium:  &entities.PremiumService{DB: db, Accounts: accountService, Log: logger},
+			Bans:     &entities.BanService{DB: db, Accounts: accountService, Log: logger},
+			Out:      os.Stdout,
+		}
+		if err = admin.Run(os.Args[1:]); err != nil {
+			logger.Error("Command failed!",
+				"function", "main::main",
+				"error", err.Error())
+			db.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
 	logger.Info(fmt.Sprintf("Starting mononoke-go version %s:%s@%s", Version, Commit, BuildDate))
 
 	if err = engine.Create(db, conf, logger); err != nil {
@@ -1,12 +1,34 @@
 package model
 
+import "time"
+
 type Accounts struct {
-	AccountID          uint32 `gorm:"primary_key;unique_index;AUTO_INCREMENT"`
-	AccountName        string `gorm:"type:varchar(61);unique_index"`
-	Password           string `gorm:"type:varchar(60)"`
-	Email              string `gorm:"type:varchar(32);unique_index"`
-	Blocked            bool
-	Age                uint8
-	LastLoginServerIdx uint32
-	Permission         uint32
+	AccountID                uint32 `gorm:"primaryKey;autoIncrement"`
+	AccountName              string `gorm:"type:varchar(61);uniqueIndex"`
+	Password                 string `gorm:"type:varchar(60)"`
+	Email                    string `gorm:"type:varchar(32);index"`
+	EmailVerificationPending bool
+	Blocked                  bool
+	Age                      uint8
+	Birthdate                *time.Time
+	LastLoginServerIdx       uint32
+	Permission               uint32
+	SecurityCode             string `gorm:"type:varchar(60)"`
+	SecurityCodeFailures     uint32
+	SecurityCodeLockedUntil  *time.Time
+	TOTPEnabled              bool
+	TOTPSecret               string `gorm:"type:varchar(64)"`
+	TOTPLastStep             int64
+	PlayTime                 uint64
+	ContinuousPlayTime       uint32
+	LastLogoutAt             *time.Time
+	// FatigueLimit overrides the fatigue categories of the configuration, nil applies them.
+	FatigueLimit *bool
+}
+
+type RecoveryCodes struct {
+	ID        uint32 `gorm:"primaryKey;autoIncrement"`
+	AccountID uint32 `gorm:"index"`
+	CodeHash  string `gorm:"type:varchar(64)"`
+	UsedAt    *time.Time
 }
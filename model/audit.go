@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+const (
+	AuditEventVersion      = "version"
+	AuditEventKeyExchange  = "key_exchange"
+	AuditEventLoginSuccess = "login_success"
+	AuditEventLoginFailed  = "login_failed"
+	AuditEventServerSelect = "server_select"
+	AuditEventGameLogin    = "game_login"
+	AuditEventLogout       = "logout"
+)
+
+type LoginAudits struct {
+	ID            uint64 `gorm:"primaryKey;autoIncrement"`
+	AccountID     uint32 `gorm:"index"`
+	AccountName   string `gorm:"type:varchar(61);index"`
+	IP            string `gorm:"type:varchar(64)"`
+	Event         string `gorm:"type:varchar(32)"`
+	Detail        string `gorm:"type:varchar(255)"`
+	ClientVersion int32
+	ServerIdx     uint32
+	PlayTime      uint32
+	CreatedAt     time.Time `gorm:"index"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type IPBans struct {
+	ID        uint32 `gorm:"primaryKey;autoIncrement"`
+	Network   string `gorm:"type:varchar(64);uniqueIndex"`
+	Reason    string `gorm:"type:varchar(255)"`
+	CreatedBy string `gorm:"type:varchar(61)"`
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+}
+
+type AccountBans struct {
+	ID        uint32 `gorm:"primaryKey;autoIncrement"`
+	AccountID uint32 `gorm:"index"`
+	StartsAt  time.Time
+	EndsAt    *time.Time
+	Reason    string `gorm:"type:varchar(255)"`
+	Issuer    string `gorm:"type:varchar(61)"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type EulaVersions struct {
+	Version     uint32 `gorm:"primaryKey;autoIncrement:false"`
+	Description string `gorm:"type:varchar(255)"`
+	PublishedAt time.Time
+}
+
+type EulaAcceptances struct {
+	ID         uint32 `gorm:"primaryKey;autoIncrement"`
+	AccountID  uint32 `gorm:"uniqueIndex:idx_eula_acceptance"`
+	Version    uint32 `gorm:"uniqueIndex:idx_eula_acceptance"`
+	AcceptedAt time.Time
+}
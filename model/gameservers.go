@@ -0,0 +1,14 @@
+package model
+
+// GameServers overrides how a game server is shown in the server list, empty values keep the reported ones.
+type GameServers struct {
+	ServerIdx     uint32 `gorm:"primaryKey;autoIncrement:false"`
+	Name          string `gorm:"type:varchar(20)"`
+	IP            string `gorm:"type:varchar(15)"`
+	Port          int32
+	ScreenshotURL string `gorm:"type:varchar(255)"`
+	AdultServer   *bool
+	DisplayOrder  int32
+	Hidden        bool
+	StaffOnly     bool
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type MacStamps struct {
+	ID          uint32 `gorm:"primaryKey;autoIncrement"`
+	AccountID   uint32 `gorm:"uniqueIndex:idx_mac_stamp_account"`
+	MacStamp    string `gorm:"type:varchar(16);uniqueIndex:idx_mac_stamp_account;index"`
+	IP          string `gorm:"type:varchar(45)"`
+	Logins      uint32
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+type HardwareBans struct {
+	ID        uint32 `gorm:"primaryKey;autoIncrement"`
+	MacStamp  string `gorm:"type:varchar(16);uniqueIndex"`
+	Reason    string `gorm:"type:varchar(255)"`
+	CreatedBy string `gorm:"type:varchar(61)"`
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+}
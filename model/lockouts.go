@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+const (
+	LockoutKindAccount = "account"
+	LockoutKindIP      = "ip"
+)
+
+type LoginLockouts struct {
+	ID          uint32 `gorm:"primaryKey;autoIncrement"`
+	Kind        string `gorm:"type:varchar(16);uniqueIndex:idx_lockout_subject"`
+	Subject     string `gorm:"type:varchar(64);uniqueIndex:idx_lockout_subject"`
+	Failures    uint32
+	WindowStart time.Time
+	LockCount   uint32
+	LockedUntil time.Time
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type Maintenances struct {
+	ID         uint32 `gorm:"primaryKey;autoIncrement"`
+	AllServers bool
+	ServerIdx  uint32
+	Message    string `gorm:"type:varchar(255)"`
+	CreatedAt  time.Time
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type PremiumEntitlements struct {
+	ID        uint32 `gorm:"primaryKey;autoIncrement"`
+	AccountID uint32 `gorm:"index"`
+	StartsAt  time.Time
+	EndsAt    *time.Time
+	Reason    string `gorm:"type:varchar(255)"`
+	CreatedAt time.Time
+}
+
+// EventCodes assigns an event code to an account, entries without AccountID belong to a campaign for all accounts.
+type EventCodes struct {
+	ID        uint32 `gorm:"primaryKey;autoIncrement"`
+	AccountID uint32 `gorm:"index"`
+	Code      uint32
+	Campaign  string `gorm:"type:varchar(64)"`
+	StartsAt  time.Time
+	EndsAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+const (
+	TokenPurposeVerifyEmail   = "verify_email"
+	TokenPurposeResetPassword = "reset_password"
+	TokenPurposeLauncher      = "launcher"
+)
+
+type AccountTokens struct {
+	ID        uint32    `gorm:"primaryKey;autoIncrement"`
+	AccountID uint32    `gorm:"index"`
+	Purpose   string    `gorm:"type:varchar(16)"`
+	TokenHash string    `gorm:"type:varchar(64);uniqueIndex"`
+	ExpiresAt time.Time `gorm:"index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
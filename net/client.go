@@ -29,6 +29,11 @@ func (c *Client) GetEndpoint() string {
 	return c.conn.RemoteAddr().String()
 }
 
+// Get the remote IP address without port.
+func (c *Client) GetIP() string {
+	return remoteIP(c.conn.RemoteAddr())
+}
+
 // Read client data from channel.
 func (c *Client) listen(key string) {
 	if c.Server.encryptClient {
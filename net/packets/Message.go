@@ -29,7 +29,7 @@ const (
 	ResultAccessDenied                              = 6
 	TS_RESULT_UNKNOWN                               = 7
 	TS_RESULT_DB_ERROR                              = 8
-	TS_RESULT_ALREADY_EXIST                         = 9
+	ResultAlreadyExist                              = 9
 	TS_RESULT_NOT_ENOUGH_MONEY                      = 10
 	TS_RESULT_TOO_HEAVY                             = 11
 	TS_RESULT_NOT_ENOUGH_JP                         = 12
@@ -60,8 +60,8 @@ const (
 	ResultTooYoung                                  = 38
 	TS_RESULT_WITHDRAW_WAITING                      = 39
 	TS_RESULT_REALNAME_REQUIRED                     = 40
-	TS_RESULT_GAMETIME_TIRED_STAMINA_SAVER          = 41
-	TS_RESULT_GAMETIME_HARMFUL_STAMINA_SAVER        = 42
+	ResultGameTimeTired                             = 41
+	ResultGameTimeHarmful                           = 42
 	TS_RESULT_NOT_ACTABLE_IN_SIEGE_OR_RAID          = 44
 	TS_RESULT_NOT_ACTABLE_IN_SECROUTE               = 45
 	TS_RESULT_NOT_ACTABLE_IN_EVENTMAP               = 46
@@ -72,13 +72,13 @@ const (
 	TS_RESULT_NOT_ACTABLE_WHILE_USING_STORAGE       = 51
 	TS_RESULT_NOT_ACTABLE_WHILE_TRADING             = 52
 	TS_RESULT_TOO_MUCH_MONEY                        = 53
-	TS_RESULT_PASSWORD_MISMATCH                     = 54
+	ResultPasswordMismatch                          = 54
 	TS_RESULT_NOT_ACTABLE_WHILE_USING_BOOTH         = 55
 	TS_RESULT_NOT_ACTABLE_IN_HUNTAHOLIC             = 56
 	TS_RESULT_TARGET_IN_HUNTAHOLIC                  = 57
 	TS_RESULT_NOT_ENOUGH_HUNTAHOLIC_POINT           = 58
 	TS_RESULT_ACTABLE_IN_ONLY_HUNTAHOLIC            = 59
-	TS_RESULT_IP_BLOCKED                            = 60
+	ResultIPBlocked                                 = 60
 	TS_RESULT_ALREADY_IN_COMPETE                    = 61
 	TS_RESULT_NOT_IN_COMPETE                        = 62
 	TS_RESULT_WAITING_COMPETE_REQUEST_ANSWER        = 63
@@ -93,7 +93,7 @@ const (
 	TS_RESULT_ACTABLE_IN_ONLY_DEATHMATCH            = 73
 	TS_RESULT_BLOCK_CHAT                            = 74
 	TS_RESULT_ENHANCE_LIMIT                         = 76
-	TS_RESULT_PENDING                               = 77
+	ResultPending                                   = 77
 	TS_RESULT_NOT_ACTABLE_IN_SECRET_DUNGEON         = 78
 	TS_RESULT_TARGET_IN_SECRET_DUNGEON              = 79
 	TS_RESULT_ALREADY_SUPER_SAVER                   = 80
@@ -119,7 +119,7 @@ const (
 	TS_RESULT_NOT_ENOUGH_ARENA_POINT                = 99
 	TS_RESULT_SUCCESS_WITHOUT_NOTICE                = 101
 	TS_RESULT_WEBZEN_DUPLICATE_ACCOUNT              = 102
-	TS_RESULT_WEBZEN_NEED_ACCEPT_EULA               = 103
+	ResultNeedAcceptEula                            = 103
 )
 
 type Message struct {
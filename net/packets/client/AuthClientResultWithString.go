@@ -6,9 +6,14 @@ import (
 
 const AuthClientResultWithStringID = 10002
 
+// AuthClientResultWithStringVersion is the first client version able to display the message.
+const AuthClientResultWithStringVersion = packets.Version520
+
 type AuthClientResultWithString struct {
-	Header      packets.Message
-	Body        AuthClientResult
-	MessageSize uint32
-	Message     []byte `byteSize:"MessageSize"`
+	Header           packets.Message
+	RequestMessageID uint16
+	Result           uint16
+	LoginFlag        int32
+	MessageSize      uint32
+	Message          []byte `byteSize:"MessageSize"`
 }
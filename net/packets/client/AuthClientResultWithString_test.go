@@ -0,0 +1,40 @@
+package client_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"mononoke-go/net/packets"
+	"mononoke-go/net/packets/client"
+	"mononoke-go/utils"
+	"reflect"
+	"testing"
+)
+
+func TestAuthClientResultWithString(t *testing.T) {
+	message := []byte("Banned\x00")
+	pkt := client.AuthClientResultWithString{
+		Header:           packets.Message{HeaderMessageSize: 0, HeaderMessageId: 10002, HeaderMessageChecksum: 0},
+		RequestMessageID: client.ClientAuthAccountID,
+		Result:           packets.ResultAccessDenied,
+		LoginFlag:        client.LoginFlagAccountBlockWarning,
+		MessageSize:      uint32(len(message)),
+		Message:          message,
+	}
+	result, err := utils.Marshal(binary.LittleEndian, pkt, packets.Version967)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	if len(result) != 7+2+2+4+4+len(message) {
+		t.Errorf("invalid length. Expected %d, received %d", 7+2+2+4+4+len(message), len(result))
+	}
+
+	reader := bytes.NewBuffer(result)
+	newPkt := client.AuthClientResultWithString{}
+	err = utils.Unmarshal(reader, binary.LittleEndian, &newPkt, packets.Version967)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	if !reflect.DeepEqual(pkt, newPkt) {
+		t.Errorf("Invalid result. Expected %v, received %v", pkt, newPkt)
+	}
+}
@@ -0,0 +1,83 @@
+package client_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"mononoke-go/net/packets"
+	"mononoke-go/net/packets/client"
+	"mononoke-go/utils"
+	"reflect"
+	"testing"
+)
+
+func TestAuthClientSelectServerPlainKey(t *testing.T) {
+	pkt := client.AuthClientSelectServer{
+		Header:      packets.Message{HeaderMessageId: client.AuthClientSelectServerID},
+		Result:      packets.ResultSuccess,
+		OneTimeKey:  0x0123456789ABCDEF,
+		PendingTime: 0,
+	}
+	for _, version := range []int{packets.Version410, packets.Version740, 0x080100} {
+		result, err := utils.Marshal(binary.LittleEndian, pkt, version)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if len(result) != 7+2+8+4 {
+			t.Errorf("version %#x: invalid length. Expected %d, received %d", version, 7+2+8+4, len(result))
+		}
+
+		newPkt := client.AuthClientSelectServer{}
+		if err = utils.Unmarshal(bytes.NewBuffer(result), binary.LittleEndian, &newPkt, version); err != nil {
+			t.Fatal(err.Error())
+		}
+		if !reflect.DeepEqual(pkt, newPkt) {
+			t.Errorf("version %#x: Invalid result. Expected %v, received %v", version, pkt, newPkt)
+		}
+	}
+}
+
+func TestAuthClientSelectServerEncryptedKey(t *testing.T) {
+	sessionKey := []byte("0123456789abcdefFEDCBA9876543210")
+	oneTimeKey := uint64(0x0123456789ABCDEF)
+	pendingTime := uint32(30)
+	plain := binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint64(nil, oneTimeKey), pendingTime)
+	encrypted, err := utils.EncryptAESCBC(sessionKey, plain)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	pkt := client.AuthClientSelectServer{
+		Header:      packets.Message{HeaderMessageId: client.AuthClientSelectServerID},
+		Result:      packets.ResultSuccess,
+		PendingTime: 0,
+	}
+	pkt.EncryptedSize = int32(copy(pkt.EncryptedData[:], encrypted))
+
+	for _, version := range []int{packets.Version811, packets.Version920, packets.Version967} {
+		result, err := utils.Marshal(binary.LittleEndian, pkt, version)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if len(result) != 7+2+4+24+4 {
+			t.Errorf("version %#x: invalid length. Expected %d, received %d", version, 7+2+4+24+4, len(result))
+		}
+
+		newPkt := client.AuthClientSelectServer{}
+		if err = utils.Unmarshal(bytes.NewBuffer(result), binary.LittleEndian, &newPkt, version); err != nil {
+			t.Fatal(err.Error())
+		}
+		if !reflect.DeepEqual(pkt, newPkt) {
+			t.Errorf("version %#x: Invalid result. Expected %v, received %v", version, pkt, newPkt)
+		}
+
+		decrypted, err := utils.DecryptAESCBC(sessionKey, newPkt.EncryptedData[:newPkt.EncryptedSize])
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if len(decrypted) != 12 || binary.LittleEndian.Uint64(decrypted) != oneTimeKey ||
+			binary.LittleEndian.Uint32(decrypted[8:]) != pendingTime {
+			t.Errorf("version %#x: decrypted %#v, want key %#x and pending time %d",
+				version, decrypted, oneTimeKey, pendingTime)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package game
+
+import "mononoke-go/net/packets"
+
+// AuthGameChallengeID is not part of the original protocol, it is sent to game servers with a configured secret.
+const AuthGameChallengeID = 20021
+
+type AuthGameChallenge struct {
+	Header    packets.Message
+	Challenge [32]byte
+}
@@ -4,6 +4,12 @@ import "mononoke-go/net/packets"
 
 const AuthGameClientLoginID = 20011
 
+const (
+	PCBangUserNone    = 0
+	PCBangUserPCBang  = 1
+	PCBangUserPremium = 2
+)
+
 type AuthGameClientLogin struct {
 	Header               packets.Message
 	Account              [61]byte
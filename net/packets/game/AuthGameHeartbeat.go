@@ -0,0 +1,12 @@
+//nolint:revive // It has to be that way to stay original
+package game
+
+import "mononoke-go/net/packets"
+
+// AuthGameHeartbeatID is not part of the original protocol, game servers supporting it answer with GameAuthHeartbeat.
+const AuthGameHeartbeatID = 20023
+
+type AuthGameHeartbeat struct {
+	Header   packets.Message
+	Sequence uint32
+}
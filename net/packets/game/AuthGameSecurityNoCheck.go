@@ -2,6 +2,8 @@ package game
 
 import "mononoke-go/net/packets"
 
+const AuthGameSecurityNoCheckID = 40002
+
 type AuthGameSecurityNoCheck struct {
 	Header  packets.Message
 	Account [61]byte
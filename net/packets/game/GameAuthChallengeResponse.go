@@ -0,0 +1,11 @@
+package game
+
+import "mononoke-go/net/packets"
+
+// GameAuthChallengeResponseID is not part of the original protocol, it answers AuthGameChallenge.
+const GameAuthChallengeResponseID = 20022
+
+type GameAuthChallengeResponse struct {
+	Header   packets.Message
+	Response [32]byte
+}
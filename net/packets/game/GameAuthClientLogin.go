@@ -10,3 +10,12 @@ type GameAuthClientLogin struct {
 	Account    [61]byte
 	OneTimeKey uint64
 }
+
+// GameAuthClientLoginWithIP is not part of the original protocol, game servers may append the IP of the client
+// to GameAuthClientLogin so the one-time key can be checked against the IP it was issued to.
+type GameAuthClientLoginWithIP struct {
+	Header     packets.Message
+	Account    [61]byte
+	OneTimeKey uint64
+	ClientIP   [46]byte
+}
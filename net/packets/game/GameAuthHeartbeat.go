@@ -0,0 +1,12 @@
+//nolint:revive // It has to be that way to stay original
+package game
+
+import "mononoke-go/net/packets"
+
+// GameAuthHeartbeatID is not part of the original protocol, it answers AuthGameHeartbeat with the same sequence.
+const GameAuthHeartbeatID = 20024
+
+type GameAuthHeartbeat struct {
+	Header   packets.Message
+	Sequence uint32
+}
@@ -0,0 +1,13 @@
+//nolint:revive // It has to be that way to stay original
+package game
+
+import "mononoke-go/net/packets"
+
+// GameAuthUserCountID is not part of the original protocol, game servers may send it to report their load.
+const GameAuthUserCountID = 20020
+
+type GameAuthUserCount struct {
+	Header    packets.Message
+	UserCount uint32
+	Capacity  uint32
+}
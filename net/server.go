@@ -1,10 +1,12 @@
 package net
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"mononoke-go/net/packets"
 	"net"
+	"time"
 )
 
 // TCP Server.
@@ -13,11 +15,18 @@ type Server struct {
 	Log                      *slog.Logger
 	encryptClient            bool
 	encryptionKey            string
+	keepAlive                time.Duration
+	onAcceptConnection       func(remoteIP string) bool
 	onNewClientCallback      func(c *Client)
 	onClientConnectionClosed func(c *Client, err error)
 	onNewMessage             func(c *Client, header packets.Message, message []byte)
 }
 
+// Called right after a connection is accepted, returning false closes it before a Client is created.
+func (s *Server) OnAcceptConnection(callback func(remoteIP string) bool) {
+	s.onAcceptConnection = callback
+}
+
 // Called right after Server starts listening new client.
 func (s *Server) OnNewClient(callback func(c *Client)) {
 	s.onNewClientCallback = callback
@@ -33,6 +42,11 @@ func (s *Server) OnNewMessage(callback func(c *Client, header packets.Message, m
 	s.onNewMessage = callback
 }
 
+// SetKeepAlive enables TCP keepalive probes with the given period on accepted connections, 0 keeps the system default.
+func (s *Server) SetKeepAlive(period time.Duration) {
+	s.keepAlive = period
+}
+
 // Listen starts network Server.
 func (s *Server) Listen() error {
 	var listener net.Listener
@@ -45,7 +59,22 @@ func (s *Server) Listen() error {
 	defer listener.Close()
 
 	for {
-		conn, _ := listener.Accept()
+		conn, acceptErr := listener.Accept()
+		if acceptErr != nil {
+			if errors.Is(acceptErr, net.ErrClosed) {
+				return acceptErr
+			}
+			s.Log.Error(fmt.Sprintf("Error accepting connection: %s", acceptErr))
+			continue
+		}
+		if !s.onAcceptConnection(remoteIP(conn.RemoteAddr())) {
+			conn.Close()
+			continue
+		}
+		if tcpConn, ok := conn.(*net.TCPConn); ok && s.keepAlive > 0 {
+			_ = tcpConn.SetKeepAlive(true)
+			_ = tcpConn.SetKeepAlivePeriod(s.keepAlive)
+		}
 		client := Client{
 			conn:   conn,
 			Server: s,
@@ -65,9 +94,19 @@ func NewTCPServer(address string, encrypt bool, key string, log *slog.Logger) *S
 		encryptionKey: key,
 	}
 
+	serverInstance.OnAcceptConnection(func(_ string) bool { return true })
 	serverInstance.OnNewClient(func(_ *Client) {})
 	serverInstance.OnNewMessage(func(_ *Client, _ packets.Message, _ []byte) {})
 	serverInstance.OnClientConnectionClosed(func(_ *Client, _ error) {})
 
 	return serverInstance
 }
+
+// Get the IP address of a remote address without port.
+func remoteIP(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
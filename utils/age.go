@@ -0,0 +1,14 @@
+package utils
+
+import "time"
+
+// AgeAt returns the age in full years of someone born at birthdate at the time t.
+// People born on February 29 turn a year older on March 1 in non-leap years.
+func AgeAt(birthdate, t time.Time) int {
+	t = t.In(birthdate.Location())
+	age := t.Year() - birthdate.Year()
+	if t.Month() < birthdate.Month() || (t.Month() == birthdate.Month() && t.Day() < birthdate.Day()) {
+		age--
+	}
+	return max(age, 0)
+}
@@ -0,0 +1,32 @@
+package utils_test
+
+import (
+	"mononoke-go/utils"
+	"testing"
+	"time"
+)
+
+func TestAgeAt(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		birthdate time.Time
+		at        time.Time
+		want      int
+	}{
+		{date(2000, time.May, 10), date(2018, time.May, 9), 17},
+		{date(2000, time.May, 10), date(2018, time.May, 10), 18},
+		{date(2000, time.May, 10), date(2018, time.December, 31), 18},
+		{date(2004, time.February, 29), date(2022, time.February, 28), 17},
+		{date(2004, time.February, 29), date(2022, time.March, 1), 18},
+		{date(2004, time.February, 29), date(2024, time.February, 29), 20},
+		{date(2030, time.January, 1), date(2020, time.January, 1), 0},
+	}
+	for _, test := range tests {
+		if got := utils.AgeAt(test.birthdate, test.at); got != test.want {
+			t.Errorf(`AgeAt(%s, %s) = %d, want %d`,
+				test.birthdate.Format(time.DateOnly), test.at.Format(time.DateOnly), got, test.want)
+		}
+	}
+}
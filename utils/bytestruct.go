@@ -360,6 +360,13 @@ func Marshal(order binary.ByteOrder, v interface{}, version int) ([]byte, error)
 		f := valueOfField.Field(i)
 		t := typeOfField.Field(i)
 
+		if value, ok := t.Tag.Lookup("version"); ok {
+			ver1, ver2 := getVersionAsIntFromTag(value)
+			if version < ver1 || version > ver2 {
+				continue
+			}
+		}
+
 		checkKind := t.Type.Kind()
 		if kind, kindOk := t.Tag.Lookup("subtype"); kindOk { //nolint:nestif // Fine.
 			subVersion, versOk := t.Tag.Lookup("subversion")
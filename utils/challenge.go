@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+const ChallengeSize = 32
+
+// GenerateChallenge creates the random challenge a game server has to answer.
+func GenerateChallenge() ([ChallengeSize]byte, error) {
+	var challenge [ChallengeSize]byte
+	_, err := rand.Read(challenge[:])
+	return challenge, err
+}
+
+// ChallengeResponse is the HMAC-SHA256 of the challenge followed by the little endian ServerIdx, keyed with
+// the shared secret of the game server.
+func ChallengeResponse(secret string, challenge [ChallengeSize]byte, serverIdx uint16) [sha256.Size]byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(challenge[:])
+	mac.Write(binary.LittleEndian.AppendUint16(nil, serverIdx))
+	var response [sha256.Size]byte
+	copy(response[:], mac.Sum(nil))
+	return response
+}
+
+// VerifyChallengeResponse compares the response in constant time.
+func VerifyChallengeResponse(secret string, challenge [ChallengeSize]byte, serverIdx uint16,
+	response [sha256.Size]byte) bool {
+	expected := ChallengeResponse(secret, challenge, serverIdx)
+	return hmac.Equal(expected[:], response[:])
+}
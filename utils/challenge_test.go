@@ -0,0 +1,43 @@
+package utils_test
+
+import (
+	"encoding/hex"
+	"mononoke-go/utils"
+	"testing"
+)
+
+func TestChallengeResponse(t *testing.T) {
+	var challenge [utils.ChallengeSize]byte
+	for i := range challenge {
+		challenge[i] = byte(i)
+	}
+	want := "0fb881bb07675aabc8577dcc06fc6be2f563e3d6f08c5bdf4f3cd3ea551a1f4c"
+	response := utils.ChallengeResponse("secret", challenge, 3)
+	if got := hex.EncodeToString(response[:]); got != want {
+		t.Errorf(`ChallengeResponse() = %s, want %s`, got, want)
+	}
+
+	if !utils.VerifyChallengeResponse("secret", challenge, 3, response) {
+		t.Errorf(`VerifyChallengeResponse() rejected a valid response`)
+	}
+	if utils.VerifyChallengeResponse("other", challenge, 3, response) {
+		t.Errorf(`VerifyChallengeResponse() accepted a response with the wrong secret`)
+	}
+	if utils.VerifyChallengeResponse("secret", challenge, 4, response) {
+		t.Errorf(`VerifyChallengeResponse() accepted a response for another ServerIdx`)
+	}
+}
+
+func TestGenerateChallenge(t *testing.T) {
+	first, err := utils.GenerateChallenge()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	second, err := utils.GenerateChallenge()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if first == second {
+		t.Errorf(`GenerateChallenge() returned the same challenge twice`)
+	}
+}
@@ -1,12 +1,16 @@
 package utils
 
 import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/md5"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/hex"
 	"encoding/pem"
+	"errors"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -65,13 +69,55 @@ func EncryptWithPublicKey(msg []byte, pub *rsa.PublicKey) ([]byte, error) {
 	return ciphertext, nil
 }
 
-func PKCS5Trimming(encrypt []byte) []byte {
-	if len(encrypt) == 0 {
-		return encrypt
+var ErrInvalidPadding = errors.New("invalid padding")
+
+// AESSessionKeySize is the size of the AES key exchanged with the client, 16 bytes key followed by 16 bytes IV.
+const AESSessionKeySize = 32
+
+// Pads the data to a multiple of the block size as described in PKCS #7.
+func PKCS7Padding(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	return append(data, bytes.Repeat([]byte{byte(padding)}, padding)...)
+}
+
+// Encrypts the PKCS #7 padded data with AES-CBC, using the first half of the session key as key and the second as IV.
+func EncryptAESCBC(sessionKey, data []byte) ([]byte, error) {
+	if len(sessionKey) != AESSessionKeySize {
+		return nil, aes.KeySizeError(len(sessionKey))
 	}
-	padding := encrypt[len(encrypt)-1]
-	if len(encrypt)-int(padding) < 0 {
-		return encrypt
+	block, err := aes.NewCipher(sessionKey[:16])
+	if err != nil {
+		return nil, err
+	}
+	plain := PKCS7Padding(append([]byte(nil), data...), aes.BlockSize)
+	encrypted := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, sessionKey[16:]).CryptBlocks(encrypted, plain)
+	return encrypted, nil
+}
+
+// Decrypts data encrypted with EncryptAESCBC and removes the padding.
+func DecryptAESCBC(sessionKey, encrypted []byte) ([]byte, error) {
+	if len(sessionKey) != AESSessionKeySize {
+		return nil, aes.KeySizeError(len(sessionKey))
+	}
+	if len(encrypted) == 0 || len(encrypted)%aes.BlockSize != 0 {
+		return nil, ErrInvalidPadding
+	}
+	block, err := aes.NewCipher(sessionKey[:16])
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, sessionKey[16:]).CryptBlocks(plain, encrypted)
+
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, ErrInvalidPadding
+	}
+	for _, b := range plain[len(plain)-padding:] {
+		if int(b) != padding {
+			return nil, ErrInvalidPadding
+		}
 	}
-	return encrypt[:len(encrypt)-int(padding)]
+	return plain[:len(plain)-padding], nil
 }
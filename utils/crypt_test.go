@@ -32,3 +32,46 @@ func TestVerifyPassword(t *testing.T) {
 		t.Errorf(`Verifypassword("helloworld", "$2a$14$YYtz2pCu3YBI8fOVYUSYuOXAgkBzeOZO2k02p/JqUpUFzBYJ8AE9O") = false`)
 	}
 }
+
+func TestEncryptAESCBC(t *testing.T) {
+	sessionKey := make([]byte, utils.AESSessionKeySize)
+	for i := range sessionKey {
+		sessionKey[i] = byte(i)
+	}
+	for _, size := range []int{0, 8, 15, 16, 17} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(0xA0 + i)
+		}
+		encrypted, err := utils.EncryptAESCBC(sessionKey, data)
+		if err != nil {
+			t.Fatalf("EncryptAESCBC() threw error %s", err.Error())
+		}
+		if want := (size/16 + 1) * 16; len(encrypted) != want {
+			t.Errorf("EncryptAESCBC() of %d bytes returned %d bytes, want %d", size, len(encrypted), want)
+		}
+		decrypted, err := utils.DecryptAESCBC(sessionKey, encrypted)
+		if err != nil {
+			t.Fatalf("DecryptAESCBC() threw error %s", err.Error())
+		}
+		if !reflect.DeepEqual(data, decrypted) {
+			t.Errorf("DecryptAESCBC() = %#v, want %#v", decrypted, data)
+		}
+	}
+}
+
+func TestDecryptAESCBCInvalid(t *testing.T) {
+	sessionKey := make([]byte, utils.AESSessionKeySize)
+	if _, err := utils.EncryptAESCBC(sessionKey[:16], []byte{1}); err == nil {
+		t.Error("EncryptAESCBC() accepted a 16 byte session key")
+	}
+	if _, err := utils.DecryptAESCBC(sessionKey, make([]byte, 15)); err == nil {
+		t.Error("DecryptAESCBC() accepted data which isn't a multiple of the block size")
+	}
+
+	encrypted, _ := utils.EncryptAESCBC(sessionKey, []byte{1, 2, 3})
+	encrypted[len(encrypted)-1] ^= 0xFF
+	if _, err := utils.DecryptAESCBC(sessionKey, encrypted); err == nil {
+		t.Error("DecryptAESCBC() accepted corrupted padding")
+	}
+}
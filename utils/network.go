@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// Parses a single IP address or a CIDR range, single IPs are returned as a host prefix.
+func ParseIPOrCIDR(value string) (netip.Prefix, error) {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// Checks if the IP is part of the given prefix, IPv4-mapped IPv6 addresses are compared as IPv4.
+func PrefixContainsIP(prefix netip.Prefix, ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	return prefix.Contains(addr.Unmap())
+}
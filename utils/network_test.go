@@ -0,0 +1,49 @@
+package utils_test
+
+import (
+	"mononoke-go/utils"
+	"testing"
+)
+
+func TestParseIPOrCIDR(t *testing.T) {
+	tests := map[string]string{
+		"127.0.0.1":      "127.0.0.1/32",
+		" 10.0.0.1 ":     "10.0.0.1/32",
+		"10.1.2.3/8":     "10.0.0.0/8",
+		"::ffff:1.2.3.4": "1.2.3.4/32",
+		"2001:db8::/32":  "2001:db8::/32",
+	}
+	for input, want := range tests {
+		prefix, err := utils.ParseIPOrCIDR(input)
+		if err != nil {
+			t.Errorf(`ParseIPOrCIDR(%q) threw error %s`, input, err.Error())
+			continue
+		}
+		if prefix.String() != want {
+			t.Errorf(`ParseIPOrCIDR(%q) = %s, want %s`, input, prefix.String(), want)
+		}
+	}
+
+	if _, err := utils.ParseIPOrCIDR("not an ip"); err == nil {
+		t.Errorf(`ParseIPOrCIDR("not an ip") didn't throw an error`)
+	}
+}
+
+func TestPrefixContainsIP(t *testing.T) {
+	prefix, err := utils.ParseIPOrCIDR("192.168.0.0/16")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !utils.PrefixContainsIP(prefix, "192.168.10.20") {
+		t.Errorf("192.168.10.20 should be part of %s", prefix)
+	}
+	if !utils.PrefixContainsIP(prefix, "::ffff:192.168.1.1") {
+		t.Errorf("::ffff:192.168.1.1 should be part of %s", prefix)
+	}
+	if utils.PrefixContainsIP(prefix, "10.0.0.1") {
+		t.Errorf("10.0.0.1 shouldn't be part of %s", prefix)
+	}
+	if utils.PrefixContainsIP(prefix, "garbage") {
+		t.Errorf("garbage shouldn't be part of %s", prefix)
+	}
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const (
+	tokenBytes = 32
+	// LauncherTokenLength fits into the 32 byte password field of the oldest clients.
+	LauncherTokenLength = 24
+	launcherTokenBytes  = LauncherTokenLength * 3 / 4
+)
+
+// GenerateToken creates a random URL safe token.
+func GenerateToken() (string, error) {
+	return generateToken(tokenBytes)
+}
+
+// GenerateLauncherToken creates a random URL safe token which clients can send instead of a password.
+func GenerateLauncherToken() (string, error) {
+	return generateToken(launcherTokenBytes)
+}
+
+func generateToken(size int) (string, error) {
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Hashes a token for storage, tokens have enough entropy to not need bcrypt.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
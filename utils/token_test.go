@@ -0,0 +1,43 @@
+package utils_test
+
+import (
+	"mononoke-go/utils"
+	"testing"
+)
+
+func TestGenerateToken(t *testing.T) {
+	first, err := utils.GenerateToken()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	second, err := utils.GenerateToken()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(first) != 43 {
+		t.Errorf(`GenerateToken() returned %d characters, want 43`, len(first))
+	}
+	if first == second {
+		t.Errorf(`GenerateToken() returned the same token twice`)
+	}
+}
+
+func TestGenerateLauncherToken(t *testing.T) {
+	token, err := utils.GenerateLauncherToken()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(token) != utils.LauncherTokenLength {
+		t.Errorf(`GenerateLauncherToken() returned %d characters, want %d`, len(token), utils.LauncherTokenLength)
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	want := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if got := utils.HashToken("hello"); got != want {
+		t.Errorf(`HashToken("hello") = %s, want %s`, got, want)
+	}
+	if utils.HashToken("hello") == utils.HashToken("Hello") {
+		t.Errorf(`HashToken should be case sensitive`)
+	}
+}
@@ -0,0 +1,77 @@
+package utils_test
+
+import (
+	"mononoke-go/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Secret "12345678901234567890" from RFC 6238 appendix B.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	tests := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range tests {
+		code, err := utils.TOTPCode(rfcSecret, utils.TOTPStep(time.Unix(unix, 0)))
+		if err != nil {
+			t.Errorf(`TOTPCode at %d threw error %s`, unix, err.Error())
+			continue
+		}
+		if code != want {
+			t.Errorf(`TOTPCode at %d = %s, want %s`, unix, code, want)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	if _, ok := utils.VerifyTOTP(rfcSecret, "081804", now, 1); !ok {
+		t.Errorf("Current code should be valid")
+	}
+	if _, ok := utils.VerifyTOTP(rfcSecret, "081804", now.Add(utils.TOTPPeriod*time.Second), 1); !ok {
+		t.Errorf("Previous code should be valid with skew 1")
+	}
+	if _, ok := utils.VerifyTOTP(rfcSecret, "081804", now.Add(3*utils.TOTPPeriod*time.Second), 1); ok {
+		t.Errorf("Old code shouldn't be valid")
+	}
+	if _, ok := utils.VerifyTOTP(rfcSecret, "81804", now, 1); ok {
+		t.Errorf("Short code shouldn't be valid")
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	code, err := utils.TOTPCode(secret, utils.TOTPStep(time.Now()))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, ok := utils.VerifyTOTP(secret, code, time.Now(), 1); !ok {
+		t.Errorf("Generated secret %s cannot be verified", secret)
+	}
+	if !strings.HasPrefix(utils.TOTPURI("mononoke", "test", secret), "otpauth://totp/mononoke:test?") {
+		t.Errorf("Invalid URI %s", utils.TOTPURI("mononoke", "test", secret))
+	}
+}
+
+func TestRecoveryCode(t *testing.T) {
+	code, err := utils.GenerateRecoveryCode()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(code) != utils.RecoveryCodeLength {
+		t.Errorf("Invalid length. Expected %d, got %d", utils.RecoveryCodeLength, len(code))
+	}
+	if utils.HashRecoveryCode(code) != utils.HashRecoveryCode(strings.ToLower(code)) {
+		t.Errorf("Recovery codes should be case insensitive")
+	}
+}